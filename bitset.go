@@ -0,0 +1,79 @@
+// Copyright 2022 Alex Schneider. All rights reserved.
+
+package cron
+
+import "math/bits"
+
+// bitset contains the values of a field, the bit `n % 64` of the word `n / 64` represents
+// the value `n`. The width is fixed by the maximum value of the field, e.g. one word for the
+// seconds, the minutes or the days of the month and 16 words for the milliseconds.
+type bitset []uint64
+
+/* ==================================================================================================== */
+
+// newBitset returns a new <cron.bitset> containing the given values up to the given maximum.
+func newBitset(values []int, max int) bitset {
+	b := make(bitset, max/64+1)
+
+	for _, v := range values {
+		if v >= 0 && v <= max {
+			b[v/64] |= 1 << (v % 64)
+		}
+	}
+
+	return b
+}
+
+// has reports whether the given value is set.
+func (b bitset) has(v int) bool {
+	return v >= 0 && v/64 < len(b) && b[v/64]&(1<<(v%64)) != 0
+}
+
+// next returns the first set value, that is greater than or equal to the given value.
+func (b bitset) next(v int) (int, bool) {
+	if v < 0 {
+		v = 0
+	}
+
+	for i := v / 64; i < len(b); i++ {
+		word := b[i]
+		if i == v/64 {
+			word &= ^uint64(0) << (v % 64)
+		}
+
+		if word != 0 {
+			return i*64 + bits.TrailingZeros64(word), true
+		}
+	}
+
+	return 0, false
+}
+
+// hasRange reports whether all values from min through max are set.
+func (b bitset) hasRange(min, max int) bool {
+	for v := min; v <= max; v++ {
+		if !b.has(v) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// first returns the smallest set value or 0 if the set is empty.
+func (b bitset) first() int {
+	v, _ := b.next(0)
+
+	return v
+}
+
+// values returns the sorted set values.
+func (b bitset) values() []int {
+	var values []int
+
+	for v, ok := b.next(0); ok; v, ok = b.next(v + 1) {
+		values = append(values, v)
+	}
+
+	return values
+}
@@ -0,0 +1,65 @@
+package cron
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBitset_next(t *testing.T) {
+	b := newBitset([]int{0, 5, 63, 64, 999, 1000, -1}, 999)
+
+	if len(b) != 16 {
+		t.Fatalf("expected '%d', got '%d'", 16, len(b))
+	}
+
+	type testCase struct {
+		v     int
+		exp   int
+		expOk bool
+	}
+
+	for _, tc := range []testCase{
+		{-5, 0, true},
+		{0, 0, true},
+		{1, 5, true},
+		{6, 63, true},
+		{64, 64, true},
+		{65, 999, true},
+		{1000, 0, false},
+	} {
+		got, ok := b.next(tc.v)
+		if got != tc.exp || ok != tc.expOk {
+			t.Errorf("'%d': expected '%d, %t', got '%d, %t'", tc.v, tc.exp, tc.expOk, got, ok)
+		}
+	}
+
+	if exp := []int{0, 5, 63, 64, 999}; !reflect.DeepEqual(exp, b.values()) {
+		t.Errorf("expected '%#v', got '%#v'", exp, b.values())
+	}
+
+	if !b.has(63) || b.has(62) || b.has(1000) || b.has(-1) {
+		t.Errorf("unexpected values '%#v'", b.values())
+	}
+}
+
+func TestBitset_first(t *testing.T) {
+	if got := newBitset([]int{17, 42}, 59).first(); got != 17 {
+		t.Errorf("expected '%d', got '%d'", 17, got)
+	}
+
+	if got := newBitset(nil, 59); got.first() != 0 || got.values() != nil {
+		t.Errorf("expected empty bitset, got '%#v'", got.values())
+	}
+}
+
+func TestBitset_hasRange(t *testing.T) {
+	b := newBitset(tableValues[:60], 59)
+
+	if !b.hasRange(0, 59) || !b.hasRange(17, 42) || b.hasRange(0, 60) {
+		t.Errorf("expected all values in '%#v'", b.values())
+	}
+
+	if b := newBitset([]int{0, 1, 3}, 59); !b.hasRange(0, 1) || b.hasRange(0, 3) {
+		t.Errorf("unexpected values '%#v'", b.values())
+	}
+}
@@ -0,0 +1,242 @@
+// Copyright 2022 Alex Schneider. All rights reserved.
+
+package cron
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// Calendar defines days at which a cronjob must not be executed,
+// e.g. public holidays.
+type Calendar interface {
+	// IsExcluded reports whether the day of the given date is excluded.
+	IsExcluded(date time.Time) bool
+}
+
+// DateCalendar represents a <cron.Calendar> with a fixed list of excluded dates.
+type DateCalendar struct {
+	dates map[calendarDate]struct{}
+}
+
+type calendarDate struct {
+	year  int
+	month time.Month
+	day   int
+}
+
+/* ==================================================================================================== */
+
+// NewDateCalendar returns a new <cron.DateCalendar> that excludes the given dates.
+func NewDateCalendar(dates ...time.Time) *DateCalendar {
+	c := &DateCalendar{
+		dates: make(map[calendarDate]struct{}),
+	}
+
+	for _, date := range dates {
+		c.Add(date)
+	}
+
+	return c
+}
+
+// Add excludes the day of the given date.
+func (c *DateCalendar) Add(date time.Time) {
+	c.dates[toCalendarDate(date)] = struct{}{}
+}
+
+// IsExcluded implements the <cron.Calendar> interface.
+func (c *DateCalendar) IsExcluded(date time.Time) bool {
+	_, ok := c.dates[toCalendarDate(date)]
+
+	return ok
+}
+
+// Len returns the number of excluded days.
+func (c *DateCalendar) Len() int {
+	return len(c.dates)
+}
+
+/* ==================================================================================================== */
+
+// LoadDateList reads the file with the given name and returns a new <cron.DateCalendar>.
+// See <cron.ParseDateList> for the file format.
+func LoadDateList(name string) (*DateCalendar, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseDateList(f)
+}
+
+// ParseDateList returns a new <cron.DateCalendar> from the given reader.
+//
+// Each line contains one date in the format `YYYY-MM-DD`, optionally followed by
+// any description separated by whitespace. Blank lines and lines starting with `#`
+// are ignored.
+func ParseDateList(r io.Reader) (*DateCalendar, error) {
+	c := NewDateCalendar()
+	scanner := bufio.NewScanner(r)
+
+	var line int
+
+	for scanner.Scan() {
+		line++
+
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		date, err := time.Parse("2006-01-02", reFieldsMatcher.FindString(text))
+		if err != nil {
+			return nil, fmt.Errorf("invalid date in line %d given: '%s'", line, text)
+		}
+
+		c.Add(date)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+/* ==================================================================================================== */
+
+// LoadICalendar reads the file with the given name and returns a new <cron.DateCalendar>.
+// See <cron.ParseICalendar> for the supported entries.
+func LoadICalendar(name string) (*DateCalendar, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseICalendar(f)
+}
+
+// ParseICalendar returns a new <cron.DateCalendar> from the given iCalendar (RFC 5545) data.
+//
+// Only all-day `VEVENT` entries are taken into account, e.g. `DTSTART;VALUE=DATE:20241225`.
+// An optional `DTEND` is exclusive, so that multi-day events exclude all days
+// from `DTSTART` up to the day before `DTEND`.
+func ParseICalendar(r io.Reader) (*DateCalendar, error) {
+	lines, err := unfoldICalendarLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	c := NewDateCalendar()
+
+	var inEvent bool
+	var start, end time.Time
+
+	for _, line := range lines {
+		name, value := splitICalendarLine(line)
+
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			inEvent = true
+			start, end = time.Time{}, time.Time{}
+		case name == "END" && strings.EqualFold(value, "VEVENT"):
+			inEvent = false
+
+			if start.IsZero() {
+				continue
+			}
+
+			if end.IsZero() || !end.After(start) {
+				end = start.AddDate(0, 0, 1)
+			}
+
+			for curr := start; curr.Before(end); curr = curr.AddDate(0, 0, 1) {
+				c.Add(curr)
+			}
+		case inEvent && (name == "DTSTART" || name == "DTEND"):
+			// Entries with a time of day are not all-day events.
+			date, err := time.Parse("20060102", value)
+			if err != nil {
+				if name == "DTSTART" {
+					start = time.Time{}
+				}
+
+				continue
+			}
+
+			if name == "DTSTART" {
+				start = date
+			} else {
+				end = date
+			}
+		}
+	}
+
+	return c, nil
+}
+
+func unfoldICalendarLines(r io.Reader) ([]string, error) {
+	var lines []string
+
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		text := strings.TrimRight(scanner.Text(), "\r")
+
+		// Long lines are folded by a CRLF followed by a single SP or HT.
+		if (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += text[1:]
+
+			continue
+		}
+
+		lines = append(lines, text)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+func splitICalendarLine(line string) (string, string) {
+	i := strings.Index(line, ":")
+	if i < 0 {
+		return strings.ToUpper(line), ""
+	}
+
+	name := line[:i]
+
+	// Strip the property parameters like `;VALUE=DATE`.
+	if j := strings.Index(name, ";"); j >= 0 {
+		name = name[:j]
+	}
+
+	return strings.ToUpper(strings.TrimSpace(name)), strings.TrimSpace(line[i+1:])
+}
+
+/* ==================================================================================================== */
+
+func toCalendarDate(date time.Time) calendarDate {
+	year, month, day := date.Date()
+
+	return calendarDate{year: year, month: month, day: day}
+}
+
+func isExcluded(calendar Calendar, date time.Time) bool {
+	return calendar != nil && calendar.IsExcluded(date)
+}
+
+func isBusinessDay(calendar Calendar, date time.Time) bool {
+	wd := date.Weekday()
+
+	return wd >= time.Monday && wd <= time.Friday && !isExcluded(calendar, date)
+}
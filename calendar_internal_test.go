@@ -0,0 +1,124 @@
+package cron
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCalendar_ParseDateList(t *testing.T) {
+	c, err := ParseDateList(strings.NewReader("# Holidays\n\n2022-12-25 Christmas\n 2022-12-26\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+	if c.Len() != 2 {
+		t.Errorf("expected '2', got '%d'", c.Len())
+	}
+	if !c.IsExcluded(time.Date(2022, 12, 25, 15, 0, 0, 0, startupTime.Location())) {
+		t.Errorf("expected '2022-12-25' to be excluded")
+	}
+	if c.IsExcluded(time.Date(2022, 12, 24, 0, 0, 0, 0, startupTime.Location())) {
+		t.Errorf("expected '2022-12-24' not to be excluded")
+	}
+
+	_, err = ParseDateList(strings.NewReader("2022-12-25\n25.12.2022\n"))
+	if eerr := fmt.Sprintf("%s", err); eerr != `invalid date in line 2 given: '25.12.2022'` {
+		t.Errorf("expected '%s', got '%s'", `invalid date in line 2 given: '25.12.2022'`, eerr)
+	}
+}
+
+func TestCalendar_ParseICalendar(t *testing.T) {
+	data := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"SUMMARY:Christmas",
+		"DTSTART;VALUE=DATE:20221225",
+		"DTEND;VALUE=DATE:20221227",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:New Year",
+		"DTSTART;VALUE=DATE:2023",
+		" 0101",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Meeting",
+		"DTSTART:20230105T100000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	c, err := ParseICalendar(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+	if c.Len() != 3 {
+		t.Errorf("expected '3', got '%d'", c.Len())
+	}
+
+	for _, d := range []time.Time{
+		time.Date(2022, 12, 25, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, 12, 26, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+	} {
+		if !c.IsExcluded(d) {
+			t.Errorf("expected '%s' to be excluded", d)
+		}
+	}
+}
+
+/* ==================================================================================================== */
+
+func TestCalendar_getDays(t *testing.T) {
+	type testCase struct {
+		expr string
+		t    time.Time
+		exp  []int
+	}
+
+	calendar := NewDateCalendar(
+		time.Date(2022, 12, 26, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, 12, 30, 0, 0, 0, 0, time.UTC),
+		time.Date(2022, 12, 16, 0, 0, 0, 0, time.UTC),
+	)
+
+	for _, tc := range []testCase{
+		{"1 1 1 ? 12 MON-FRI 2022", testScheduleTime, []int{1, 2, 5, 6, 7, 8, 9, 12, 13, 14, 15, 19, 20, 21, 22, 23, 27, 28, 29}},
+		{"1 1 1 LW 12 ? 2022", testScheduleTime, []int{29}},
+		{"1 1 1 17W 12 ? 2022", testScheduleTime, []int{15}},
+		{"1 1 1 25W 12 ? 2022", testScheduleTime, []int{27}},
+	} {
+		s, err := createTestScheduler(tc.expr)
+		if err != nil {
+			t.Fatalf("'%s': unexpected error: %#v", tc.expr, err)
+		}
+
+		s.calendar = calendar
+
+		got := bitset{s.getDays(tc.t)}.values()
+		if !reflect.DeepEqual(tc.exp, got) {
+			t.Errorf("'%s': expected '%#v', got '%#v'", tc.expr, tc.exp, got)
+		}
+	}
+}
+
+func TestCalendar_Next(t *testing.T) {
+	s, err := createTestScheduler("0 0 9 ? * MON-FRI *")
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+
+	s.calendar = NewDateCalendar(time.Date(2022, 12, 26, 0, 0, 0, 0, time.UTC))
+
+	// 2022-12-23 is a Friday and 2022-12-26 is an excluded Monday.
+	got, state := s.next(time.Date(2022, 12, 23, 10, 0, 0, 0, startupTime.Location()))
+	if state != StateFound {
+		t.Errorf("expected '%s', got '%s'", StateFound, state)
+	}
+
+	exp := time.Date(2022, 12, 27, 9, 0, 0, 0, startupTime.Location())
+	if got.String() != exp.String() {
+		t.Errorf("expected '%s', got '%s'", exp, got)
+	}
+}
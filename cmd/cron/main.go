@@ -0,0 +1,173 @@
+// Copyright 2022 Alex Schneider. All rights reserved.
+
+// Command cron validates and explains cron expressions.
+//
+// Usage:
+//
+//	cron validate [--dialect name] <expression>
+//	cron next [-n count] [--tz zone] [--dialect name] <expression>
+//
+// The `validate` command prints the normalized form, the description and the lint warnings
+// of the expression.
+// The `next` command additionally prints the next run times in the given timezone. The
+// command exits with the status 1 and the position of the error on invalid expressions.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alex-schneider/cron"
+)
+
+const usage = `Usage:
+  cron validate [--dialect name] <expression>
+  cron next [-n count] [--tz zone] [--dialect name] <expression>
+`
+
+// dialects contains all dialects, which can be selected by their names.
+var dialects = []cron.Dialect{
+	cron.DialectNative,
+	cron.DialectQuartz,
+	cron.DialectVixie,
+	cron.DialectSystemd,
+	cron.DialectEventBridge,
+	cron.DialectRRule,
+}
+
+/* ==================================================================================================== */
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdout, os.Stderr, time.Now()))
+}
+
+// run executes the command with the given arguments and returns the exit status.
+func run(args []string, stdout, stderr io.Writer, now time.Time) int {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+
+		return 2
+	}
+
+	command := args[0]
+	if command != "validate" && command != "next" {
+		fmt.Fprintf(stderr, "cron: unknown command '%s'\n%s", command, usage)
+
+		return 2
+	}
+
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.Usage = func() { fmt.Fprint(stderr, usage) }
+
+	dialectName := fs.String("dialect", cron.DialectNative.String(), "the dialect of the expression")
+	count := 0
+	zone := ""
+
+	if command == "next" {
+		fs.IntVar(&count, "n", 5, "the number of the next run times")
+		fs.StringVar(&zone, "tz", "Local", "the timezone of the run times")
+	}
+
+	if err := fs.Parse(args[1:]); err != nil {
+		return 2
+	} else if fs.NArg() != 1 {
+		fmt.Fprint(stderr, usage)
+
+		return 2
+	}
+
+	dialect, ok := parseDialect(*dialectName)
+	if !ok {
+		fmt.Fprintf(stderr, "cron: unknown dialect '%s'\n", *dialectName)
+
+		return 2
+	}
+
+	opts := []cron.Option{cron.WithDialect(dialect)}
+
+	location := now.Location()
+	if zone != "" {
+		loc, err := time.LoadLocation(zone)
+		if err != nil {
+			fmt.Fprintf(stderr, "cron: invalid timezone given '%s'\n", zone)
+
+			return 2
+		}
+
+		location = loc
+		opts = append(opts, cron.WithLocation(location))
+	}
+
+	expression := fs.Arg(0)
+
+	schedule, err := cron.Parse(expression, opts...)
+	if err != nil {
+		printParseError(stderr, expression, err)
+
+		return 1
+	}
+
+	// The recurrence rules and the rate expressions have no normalized form.
+	if normalized, err := cron.Normalize(expression, opts...); err == nil {
+		fmt.Fprintf(stdout, "Normalized:  %s\n", normalized)
+	}
+
+	if description, err := cron.Describe(expression, opts...); err == nil {
+		fmt.Fprintf(stdout, "Description: %s\n", description)
+	}
+
+	if warnings, err := cron.Lint(expression, opts...); err == nil {
+		for _, warning := range warnings {
+			fmt.Fprintf(stdout, "Warning:     %s\n", warning)
+		}
+	}
+
+	if command == "validate" {
+		return 0
+	}
+
+	fmt.Fprintf(stdout, "Next runs (%s):\n", location)
+
+	next := now.In(location)
+
+	for i := 0; i < count; i++ {
+		next = schedule.Next(next)
+		if next.IsZero() {
+			break
+		}
+
+		fmt.Fprintf(stdout, "  %s\n", next.In(location).Format("2006-01-02 15:04:05.000 MST Mon"))
+	}
+
+	return 0
+}
+
+// parseDialect returns the dialect with the given name.
+func parseDialect(name string) (cron.Dialect, bool) {
+	for _, dialect := range dialects {
+		if strings.EqualFold(dialect.String(), name) {
+			return dialect, true
+		}
+	}
+
+	return 0, false
+}
+
+// printParseError prints the error and marks its position in the expression if it is known.
+func printParseError(w io.Writer, expression string, err error) {
+	var pe *cron.ParseError
+	if !errors.As(err, &pe) || pe.Position < 0 {
+		fmt.Fprintf(w, "cron: %s\n", err)
+
+		return
+	}
+
+	fmt.Fprintf(w, "cron: %s at position %d\n", err, pe.Position)
+	fmt.Fprintf(w, "  %s\n  %s^\n", expression, strings.Repeat(" ", pe.Position))
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestMain_Run(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	type testCase struct {
+		args   []string
+		status int
+		stdout string
+		stderr string
+	}
+
+	tests := []testCase{
+		{
+			[]string{"next", "-n", "3", "--tz", "Asia/Tokyo", "0 0 9 ? * 1#1 *"},
+			0,
+			"Normalized:  0 0 9 ? * 1#1 *\n" +
+				"Description: At 09:00:00, on the 1st Monday of the month (Asia/Tokyo)\n" +
+				"Next runs (Asia/Tokyo):\n" +
+				"  2023-01-02 09:00:00.000 JST Mon\n" +
+				"  2023-02-06 09:00:00.000 JST Mon\n" +
+				"  2023-03-06 09:00:00.000 JST Mon\n",
+			"",
+		},
+		{
+			[]string{"next", "-n", "2", "--tz", "UTC", "--dialect", "vixie", "@hourly"},
+			0,
+			"Normalized:  0 0 * * * * *\n" +
+				"Description: At second 0, minute 0 (UTC)\n" +
+				"Next runs (UTC):\n" +
+				"  2023-01-01 01:00:00.000 UTC Sun\n" +
+				"  2023-01-01 02:00:00.000 UTC Sun\n",
+			"",
+		},
+		{
+			[]string{"validate", "0 0 12 ? * MON-FRI *"},
+			0,
+			"Normalized:  0 0 12 ? * MON-FRI *\n" +
+				"Description: At 12:00:00, on Monday through Friday\n",
+			"",
+		},
+		{
+			[]string{"validate", "* * * * * ? *"},
+			0,
+			"Normalized:  * * * * * ? *\n" +
+				"Description: At every second\n" +
+				"Warning:     position 0: the '*' in the field 'seconds' runs the cronjob every second\n",
+			"",
+		},
+		{
+			[]string{"validate", "0 0 25 * * ? *"},
+			1,
+			"",
+			"cron: invalid value in field 'hours' given: '25' at position 4\n" +
+				"  0 0 25 * * ? *\n" +
+				"      ^\n",
+		},
+		{
+			[]string{"validate", "@foo"},
+			1,
+			"",
+			"cron: unsupported macro given '@foo'\n",
+		},
+		{[]string{}, 2, "", usage},
+		{[]string{"foo"}, 2, "", "cron: unknown command 'foo'\n" + usage},
+		{[]string{"validate"}, 2, "", usage},
+		{[]string{"validate", "--dialect", "foo", "* * * * *"}, 2, "", "cron: unknown dialect 'foo'\n"},
+		{[]string{"next", "--tz", "Mars/Base", "* * * * *"}, 2, "", "cron: invalid timezone given 'Mars/Base'\n"},
+	}
+
+	for _, tc := range tests {
+		var stdout, stderr bytes.Buffer
+
+		status := run(tc.args, &stdout, &stderr, now)
+
+		if status != tc.status {
+			t.Errorf("'%v': expected '%d', got '%d'", tc.args, tc.status, status)
+		}
+
+		if stdout.String() != tc.stdout {
+			t.Errorf("'%v': expected '%s', got '%s'", tc.args, tc.stdout, stdout.String())
+		}
+
+		if stderr.String() != tc.stderr {
+			t.Errorf("'%v': expected '%s', got '%s'", tc.args, tc.stderr, stderr.String())
+		}
+	}
+}
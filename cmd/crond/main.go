@@ -0,0 +1,361 @@
+// Copyright 2022 Alex Schneider. All rights reserved.
+
+// Command crond is a minimal cron daemon, which executes the commands of a crontab file.
+//
+// Usage:
+//
+//	crond [-f crontab] [-user] [-logs dir] [-timeout duration] [-dialect name]
+//
+// The commands are executed with `/bin/sh -c` at the scheduled times. The output of each
+// job is appended to its own log file in the logs directory. The commands and all their
+// child processes are killed if they run longer than the timeout. The crontab file is
+// reloaded on SIGHUP.
+//
+// The system crontabs `/etc/crontab` and `/etc/cron.d/*` contain the user column between
+// the expression and the command, which is expected for other files with the `-user` flag.
+//
+// The expressions are parsed with the Vixie dialect by default, like by the classic cron
+// daemons. The `-dialect native` flag enables the extended syntax of the package like `R`,
+// `L`, `W` or `#`. Note that the day-of-month and the day-of-week fields are combined in the
+// native dialect, so that `0 9 * * MON` runs every day, use `0 9 ? * MON` to run on Mondays
+// only. The `-dialect quartz` flag expects the Quartz expressions with all 7 fields including
+// the year, e.g. `0 0 12 ? * MON *`.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/alex-schneider/cron"
+)
+
+// dialects contains the dialects, which can be used in the crontab files.
+var dialects = []cron.Dialect{
+	cron.DialectNative,
+	cron.DialectQuartz,
+	cron.DialectVixie,
+}
+
+// daemon executes the entries of a crontab file.
+type daemon struct {
+	crontab string
+	logDir  string
+	timeout time.Duration
+	opts    []cron.Option
+	logger  *log.Logger
+
+	// nowFn returns the current time and timerFn waits for the given duration, they are
+	// replaced in the tests.
+	nowFn   func() time.Time
+	timerFn func(d time.Duration) (<-chan time.Time, func())
+}
+
+/* ==================================================================================================== */
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// run starts the daemon with the given arguments and returns the exit status.
+func run(args []string, stderr io.Writer) int {
+	d, ok := newDaemon(args, stderr)
+	if !ok {
+		return 2
+	}
+
+	if err := os.MkdirAll(d.logDir, 0o755); err != nil {
+		fmt.Fprintf(stderr, "crond: %s\n", err)
+
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	if err := d.serve(ctx, hup); err != nil {
+		fmt.Fprintf(stderr, "crond: %s\n", err)
+
+		return 1
+	}
+
+	return 0
+}
+
+// newDaemon returns the daemon configured by the given arguments. The usage errors are
+// written to the given writer.
+func newDaemon(args []string, stderr io.Writer) (*daemon, bool) {
+	fs := flag.NewFlagSet("crond", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+
+	crontab := fs.String("f", "/etc/crontab", "the crontab file")
+	logDir := fs.String("logs", "/var/log/crond", "the directory of the log files of the jobs")
+	timeout := fs.Duration("timeout", 0, "the maximum duration of a job, no limit if 0")
+	userColumn := fs.Bool("user", false, "the crontab file contains the user column, the default for the system crontabs")
+	dialectName := fs.String("dialect", cron.DialectVixie.String(), "the dialect of the expressions")
+
+	if err := fs.Parse(args); err != nil {
+		return nil, false
+	} else if fs.NArg() != 0 {
+		fs.Usage()
+
+		return nil, false
+	}
+
+	d := &daemon{
+		crontab: *crontab,
+		logDir:  *logDir,
+		timeout: *timeout,
+		logger:  log.New(stderr, "crond: ", log.LstdFlags),
+		nowFn:   time.Now,
+		timerFn: newTimer,
+	}
+
+	// The user column is expected in the system crontabs unless the flag is given explicitly.
+	userSet := false
+	fs.Visit(func(f *flag.Flag) { userSet = userSet || f.Name == "user" })
+
+	if *userColumn || (!userSet && isSystemCrontab(d.crontab)) {
+		d.opts = append(d.opts, cron.WithUserColumn())
+	}
+
+	var hasDialect bool
+
+	for _, dialect := range dialects {
+		if strings.EqualFold(dialect.String(), *dialectName) {
+			d.opts = append(d.opts, cron.WithDialect(dialect))
+			hasDialect = true
+		}
+	}
+
+	if !hasDialect {
+		fmt.Fprintf(stderr, "crond: unsupported dialect '%s'\n", *dialectName)
+
+		return nil, false
+	}
+
+	return d, true
+}
+
+/* ==================================================================================================== */
+
+// serve schedules the entries of the crontab file until the given context is done. The
+// crontab file is reloaded on each value of the given reload channel. The running jobs
+// are not interrupted by the reloads.
+func (d *daemon) serve(ctx context.Context, reload <-chan os.Signal) error {
+	entries, err := d.load()
+	if err != nil {
+		return err
+	}
+
+	var jobs sync.WaitGroup
+	defer jobs.Wait()
+
+	for _, entry := range entries {
+		if isReboot(entry) {
+			jobs.Add(1)
+
+			go func(entry *cron.CrontabEntry) {
+				defer jobs.Done()
+
+				d.exec(ctx, entry)
+			}(entry)
+		}
+	}
+
+	for {
+		schedCtx, cancel := context.WithCancel(ctx)
+
+		var schedulers sync.WaitGroup
+
+		for _, entry := range entries {
+			if isReboot(entry) {
+				continue
+			}
+
+			schedulers.Add(1)
+
+			go func(entry *cron.CrontabEntry) {
+				defer schedulers.Done()
+
+				d.schedule(ctx, schedCtx, entry, &jobs)
+			}(entry)
+		}
+
+		select {
+		case <-ctx.Done():
+			cancel()
+			schedulers.Wait()
+
+			return nil
+		case <-reload:
+		}
+
+		cancel()
+		schedulers.Wait()
+
+		// The previous entries are kept if the crontab file cannot be read anymore.
+		if reloaded, err := d.load(); err != nil {
+			d.logger.Printf("reload failed: %s", err)
+		} else {
+			entries = reloaded
+			d.logger.Printf("reloaded %d entries from %s", len(entries), d.crontab)
+		}
+	}
+}
+
+// load reads the crontab file. The invalid lines are logged and skipped.
+func (d *daemon) load() ([]*cron.CrontabEntry, error) {
+	entries, err := cron.LoadCrontab(d.crontab, d.opts...)
+	if errs, ok := err.(cron.CrontabErrors); ok {
+		for _, e := range errs {
+			d.logger.Printf("%s: %s", d.crontab, e)
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	return entries, nil
+}
+
+// schedule executes the given entry at the scheduled times until the scheduler context is
+// done. The jobs are executed within the daemon context, so that they survive the reloads.
+func (d *daemon) schedule(ctx, schedCtx context.Context, entry *cron.CrontabEntry, jobs *sync.WaitGroup) {
+	now := d.nowFn()
+
+	for {
+		next := entry.Schedule.Next(now)
+		if next.IsZero() {
+			d.logger.Printf("line %d: no next execution of '%s'", entry.Line, entry.Expression)
+
+			return
+		}
+
+		timer, stop := d.timerFn(next.Sub(d.nowFn()))
+
+		select {
+		case <-schedCtx.Done():
+			stop()
+
+			return
+		case <-timer:
+		}
+
+		jobs.Add(1)
+
+		go func() {
+			defer jobs.Done()
+
+			d.exec(ctx, entry)
+		}()
+
+		now = next
+	}
+}
+
+// exec executes the command of the given entry and appends its output to the log file.
+func (d *daemon) exec(ctx context.Context, entry *cron.CrontabEntry) {
+	if d.timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, d.timeout)
+		defer cancel()
+	}
+
+	name := logFileName(entry)
+
+	f, err := os.OpenFile(filepath.Join(d.logDir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		d.logger.Printf("line %d: %s", entry.Line, err)
+
+		return
+	}
+	defer f.Close()
+
+	cmd := exec.Command("/bin/sh", "-c", entry.Command)
+	cmd.Stdout = f
+	cmd.Stderr = f
+	cmd.Env = os.Environ()
+
+	for k, v := range entry.Env {
+		cmd.Env = append(cmd.Env, k+"="+v)
+	}
+
+	// The command runs in its own process group, so that its child processes are killed, too.
+	setProcessGroup(cmd)
+
+	start := d.nowFn()
+	fmt.Fprintf(f, "--- %s start: %s\n", start.Format(time.RFC3339), entry.Command)
+
+	err = cmd.Start()
+	if err == nil {
+		done := make(chan struct{})
+
+		go func() {
+			select {
+			case <-ctx.Done():
+				killProcessGroup(cmd)
+			case <-done:
+			}
+		}()
+
+		err = cmd.Wait()
+		close(done)
+	}
+
+	status := "ok"
+	if ctx.Err() == context.DeadlineExceeded {
+		status = fmt.Sprintf("timeout after %s", d.timeout)
+	} else if err != nil {
+		status = err.Error()
+	}
+
+	fmt.Fprintf(f, "--- %s end: %s (%s)\n", d.nowFn().Format(time.RFC3339), status, d.nowFn().Sub(start).Round(time.Millisecond))
+
+	if status != "ok" {
+		d.logger.Printf("line %d: %s: %s", entry.Line, name, status)
+	}
+}
+
+/* ==================================================================================================== */
+
+// newTimer returns the channel of a new timer with the given duration and its stop function.
+func newTimer(d time.Duration) (<-chan time.Time, func()) {
+	timer := time.NewTimer(d)
+
+	return timer.C, func() { timer.Stop() }
+}
+
+// isSystemCrontab reports whether the given file is a system crontab with the user column.
+func isSystemCrontab(name string) bool {
+	return filepath.Clean(name) == "/etc/crontab" || filepath.Dir(filepath.Clean(name)) == "/etc/cron.d"
+}
+
+// isReboot reports whether the given entry is executed once at startup.
+func isReboot(entry *cron.CrontabEntry) bool {
+	return strings.EqualFold(entry.Expression, "@reboot")
+}
+
+// logFileName returns the name of the log file of the given entry. The name is derived from
+// the expression and the command, so that it does not change if the lines are moved.
+func logFileName(entry *cron.CrontabEntry) string {
+	h := fnv.New32a()
+	h.Write([]byte(entry.Expression + "\x00" + entry.Command))
+
+	return fmt.Sprintf("job-%08x.log", h.Sum32())
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alex-schneider/cron"
+)
+
+func newTestDaemon(t *testing.T, crontab string) (*daemon, *bytes.Buffer) {
+	dir := t.TempDir()
+
+	name := filepath.Join(dir, "crontab")
+	if err := os.WriteFile(name, []byte(crontab), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+
+	return &daemon{
+		crontab: name,
+		logDir:  dir,
+		opts:    []cron.Option{cron.WithDialect(cron.DialectNative)},
+		logger:  log.New(&buf, "", 0),
+		nowFn:   time.Now,
+		timerFn: newTimer,
+	}, &buf
+}
+
+// fakeClock replaces the clock of the daemon, its timers fire only on <fakeClock.fire>.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers chan fakeTimer
+}
+
+type fakeTimer struct {
+	d time.Duration
+	c chan time.Time
+}
+
+func newFakeClock(now time.Time) *fakeClock {
+	return &fakeClock{now: now, timers: make(chan fakeTimer)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *fakeClock) Timer(d time.Duration) (<-chan time.Time, func()) {
+	timer := fakeTimer{d: d, c: make(chan time.Time, 1)}
+	c.timers <- timer
+
+	return timer.c, func() {}
+}
+
+// wait waits for the next timer of the daemon.
+func (c *fakeClock) wait(t *testing.T) fakeTimer {
+	select {
+	case timer := <-c.timers:
+		return timer
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected a timer, got none")
+	}
+
+	return fakeTimer{}
+}
+
+// fire advances the clock to the end of the next timer and fires it.
+func (c *fakeClock) fire(t *testing.T) {
+	timer := c.wait(t)
+
+	c.mu.Lock()
+	c.now = c.now.Add(timer.d)
+	c.mu.Unlock()
+
+	timer.c <- c.Now()
+}
+
+// notifyWriter signals each written line containing the match.
+type notifyWriter struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	match string
+	ch    chan struct{}
+}
+
+func (w *notifyWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if strings.Contains(string(p), w.match) {
+		w.ch <- struct{}{}
+	}
+
+	return w.buf.Write(p)
+}
+
+func (w *notifyWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.buf.String()
+}
+
+func readLog(t *testing.T, d *daemon, entry *cron.CrontabEntry) string {
+	data, err := os.ReadFile(filepath.Join(d.logDir, logFileName(entry)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+// waitForJobs waits until the log of the entry contains the end of the given number of jobs.
+func waitForJobs(t *testing.T, d *daemon, entry *cron.CrontabEntry, n int) {
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		data, _ := os.ReadFile(filepath.Join(d.logDir, logFileName(entry)))
+		if strings.Count(string(data), " end: ") >= n {
+			return
+		} else if time.Now().After(deadline) {
+			t.Fatalf("expected '%d' jobs, got '%s'", n, data)
+		}
+	}
+}
+
+func TestMain_Load(t *testing.T) {
+	d, buf := newTestDaemon(t, "FOO=bar\n0 0 L * ? echo last\n0 25 * * * echo invalid\n")
+
+	entries, err := d.load()
+	if err != nil {
+		t.Fatalf("unexpected error '%s'", err)
+	}
+
+	if len(entries) != 1 || entries[0].Command != "echo last" {
+		t.Fatalf("expected '%s', got '%v'", "echo last", entries)
+	}
+
+	exp := "line 3: invalid value in field 'hours' given: '25'"
+	if !strings.Contains(buf.String(), exp) {
+		t.Errorf("expected '%s', got '%s'", exp, buf.String())
+	}
+
+	d.crontab = filepath.Join(d.logDir, "missing")
+	if _, err := d.load(); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestMain_Exec(t *testing.T) {
+	d, buf := newTestDaemon(t, "")
+
+	entry := &cron.CrontabEntry{
+		Line:       1,
+		Expression: "@reboot",
+		Command:    "echo $FOO; echo error >&2",
+		Env:        map[string]string{"FOO": "bar"},
+	}
+
+	d.exec(context.Background(), entry)
+
+	got := readLog(t, d, entry)
+	if !strings.Contains(got, "bar\nerror\n") || !strings.Contains(got, "end: ok") {
+		t.Errorf("expected '%s', got '%s'", "bar\nerror\n", got)
+	}
+
+	d.timeout = 50 * time.Millisecond
+	entry.Command = "sleep 5"
+
+	start := time.Now()
+	d.exec(context.Background(), entry)
+
+	if time.Since(start) > 2*time.Second {
+		t.Errorf("expected the timeout of '%s', got '%s'", d.timeout, time.Since(start))
+	}
+
+	exp := "timeout after 50ms"
+	if got := readLog(t, d, entry); !strings.Contains(got, exp) {
+		t.Errorf("expected '%s', got '%s'", exp, got)
+	}
+
+	if !strings.Contains(buf.String(), exp) {
+		t.Errorf("expected '%s', got '%s'", exp, buf.String())
+	}
+}
+
+func TestMain_Schedule(t *testing.T) {
+	d, _ := newTestDaemon(t, "")
+
+	clock := newFakeClock(time.Date(2023, 1, 1, 0, 0, 0, 500*int(time.Millisecond), time.UTC))
+	d.nowFn, d.timerFn = clock.Now, clock.Timer
+
+	schedule, err := cron.Parse("* * * * * * *")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entry := &cron.CrontabEntry{Line: 1, Expression: "* * * * * * *", Command: "echo tick", Schedule: schedule}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var jobs sync.WaitGroup
+
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		d.schedule(context.Background(), ctx, entry, &jobs)
+	}()
+
+	// Each job ends before the next one is started, so that their outputs are not interleaved.
+	clock.fire(t)
+	waitForJobs(t, d, entry, 1)
+	clock.fire(t)
+	waitForJobs(t, d, entry, 2)
+
+	// The scheduler waits for the third execution, when it is cancelled.
+	if timer := clock.wait(t); timer.d != time.Second {
+		t.Errorf("expected '%s', got '%s'", time.Second, timer.d)
+	}
+
+	cancel()
+	<-done
+	jobs.Wait()
+
+	if got := strings.Count(readLog(t, d, entry), "\ntick\n"); got != 2 {
+		t.Errorf("expected '%d', got '%d'", 2, got)
+	}
+}
+
+func TestMain_Serve(t *testing.T) {
+	d, _ := newTestDaemon(t, "@reboot echo started\n")
+
+	w := &notifyWriter{match: "reloaded", ch: make(chan struct{}, 1)}
+	d.logger = log.New(w, "", 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	reload := make(chan os.Signal, 1)
+
+	done := make(chan error)
+	go func() { done <- d.serve(ctx, reload) }()
+
+	reload <- os.Interrupt
+
+	select {
+	case <-w.ch:
+	case <-time.After(5 * time.Second):
+		t.Errorf("expected '%s', got '%s'", "reloaded", w.String())
+	}
+
+	// The running jobs are killed on shutdown, so that the test waits for the end of the job.
+	entry := &cron.CrontabEntry{Expression: "@reboot", Command: "echo started"}
+
+	waitForJobs(t, d, entry, 1)
+
+	cancel()
+
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error '%s'", err)
+	}
+
+	if got := readLog(t, d, entry); strings.Count(got, "\nstarted\n") != 1 {
+		t.Errorf("expected '%s', got '%s'", "started\n", got)
+	}
+
+	exp := "reloaded 1 entries"
+	if !strings.Contains(w.String(), exp) {
+		t.Errorf("expected '%s', got '%s'", exp, w.String())
+	}
+}
+
+func TestMain_newDaemon(t *testing.T) {
+	type testCase struct {
+		args []string
+		exp  string
+	}
+
+	for _, tc := range []testCase{
+		{nil, "root: echo hi"},
+		{[]string{"-f", "/etc/cron.d/app"}, "root: echo hi"},
+		{[]string{"-f", "/etc/crontab", "-user=false"}, ": root echo hi"},
+		{[]string{"-f", "/etc/crontabs/app"}, ": root echo hi"},
+		{[]string{"-f", "/etc/crontabs/app", "-user"}, "root: echo hi"},
+	} {
+		var stderr bytes.Buffer
+
+		d, ok := newDaemon(tc.args, &stderr)
+		if !ok {
+			t.Fatalf("'%v': unexpected error '%s'", tc.args, stderr.String())
+		}
+
+		entries, err := cron.ParseCrontab(strings.NewReader("0 9 * * MON root echo hi\n"), d.opts...)
+		if err != nil {
+			t.Errorf("'%v': unexpected error '%s'", tc.args, err)
+
+			continue
+		}
+
+		if got := entries[0].User + ": " + entries[0].Command; got != tc.exp {
+			t.Errorf("'%v': expected '%s', got '%s'", tc.args, tc.exp, got)
+		}
+
+		// The Vixie dialect runs on Mondays only.
+		next := entries[0].Schedule.Next(time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC))
+		if exp := time.Date(2023, 1, 9, 9, 0, 0, 0, time.UTC); !next.Equal(exp) {
+			t.Errorf("'%v': expected '%s', got '%s'", tc.args, exp, next)
+		}
+	}
+}
+
+func TestMain_Run(t *testing.T) {
+	var stderr bytes.Buffer
+
+	if status := run([]string{"-dialect", "systemd"}, &stderr); status != 2 {
+		t.Errorf("expected '%d', got '%d'", 2, status)
+	}
+
+	if status := run([]string{"foo"}, &stderr); status != 2 {
+		t.Errorf("expected '%d', got '%d'", 2, status)
+	}
+
+	if status := run([]string{"-f", filepath.Join(t.TempDir(), "missing"), "-logs", t.TempDir()}, &stderr); status != 1 {
+		t.Errorf("expected '%d', got '%d'", 1, status)
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alex-schneider/cron"
+)
+
+func TestMain_Exec_ProcessGroup(t *testing.T) {
+	d, _ := newTestDaemon(t, "")
+	d.timeout = 50 * time.Millisecond
+
+	pidFile := filepath.Join(d.logDir, "pid")
+
+	entry := &cron.CrontabEntry{
+		Line:       1,
+		Expression: "@reboot",
+		Command:    "sleep 5 & echo $! > " + pidFile + "; wait",
+	}
+
+	d.exec(context.Background(), entry)
+
+	data, err := os.ReadFile(pidFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The child process of the shell is killed with the shell, it is gone or a zombie.
+	stat := filepath.Join("/proc", strings.TrimSpace(string(data)), "stat")
+
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(10 * time.Millisecond) {
+		data, err := os.ReadFile(stat)
+		if err != nil || strings.Contains(string(data), ") Z ") {
+			break
+		} else if time.Now().After(deadline) {
+			t.Fatalf("expected the child process to be killed, got '%s'", data)
+		}
+	}
+}
@@ -0,0 +1,15 @@
+// Copyright 2022 Alex Schneider. All rights reserved.
+
+//go:build !unix
+
+package main
+
+import "os/exec"
+
+// setProcessGroup does nothing, the process groups are not supported.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills the process of the given started command only.
+func killProcessGroup(cmd *exec.Cmd) {
+	_ = cmd.Process.Kill()
+}
@@ -0,0 +1,371 @@
+// Copyright 2022 Alex Schneider. All rights reserved.
+
+package cron
+
+import (
+	"reflect"
+	"time"
+)
+
+// Equal reports whether both schedules are executed at the same times, e.g. `@daily`,
+// `0 0 0 * * * *` and `0 0 * * *` are equal. See <cron.Schedule.Covers> for the limits.
+func (s *Schedule) Equal(other *Schedule) bool {
+	return s.Covers(other) && other.Covers(s)
+}
+
+// Covers reports whether the schedule is executed at all times of the other schedule, e.g.
+// `0 */15 * * * ? *` covers `0 0,30 * * * ? *`. The expanded values of the fields and the
+// special characters are compared instead of the expressions. Both schedules have to use
+// the same location and the same calendar. The zero value is covered by all schedules.
+//
+// The composite schedules are compared by their operands, so that not all equivalences are
+// detected, e.g. a union of two schedules does not cover a schedule matching both of them
+// partially. The intersections of simple schedules are compared exactly, e.g. the normalized
+// form of the <cron.DialectSystemd> expressions. The recurrence rules and the rate expressions
+// only cover the identical ones, their default anchors set at parse time are not compared.
+func (s *Schedule) Covers(other *Schedule) bool {
+	if other.IsZero() {
+		return true
+	} else if s.IsZero() {
+		return false
+	}
+
+	return covers(s.spec, other.spec)
+}
+
+/* ==================================================================================================== */
+
+// covers reports whether the first spec is executed at all times of the second one.
+func covers(a, b spec) bool {
+	if s, ok := a.(*schedule); ok && s.spec != nil {
+		return covers(s.spec, b)
+	} else if s, ok := b.(*schedule); ok && s.spec != nil {
+		return covers(a, s.spec)
+	}
+
+	// The operands of the second spec are resolved first, because these rules are exact.
+	switch b := b.(type) {
+	case union:
+		for _, operand := range b {
+			if !covers(a, operand) {
+				return false
+			}
+		}
+
+		return true
+	case *exception:
+		if covers(a, b.spec) {
+			return true
+		}
+	case intersection:
+		for _, operand := range b {
+			if covers(a, operand) {
+				return true
+			}
+		}
+	}
+
+	switch a := a.(type) {
+	case union:
+		for _, operand := range a {
+			if covers(operand, b) {
+				return true
+			}
+		}
+	case intersection:
+		for _, operand := range a {
+			if !covers(operand, b) {
+				return false
+			}
+		}
+
+		return true
+	case *schedule:
+		if leaf, ok := b.(*schedule); ok {
+			return a.covers(leaf)
+		} else if leaves, ok := intersectionLeaves(b); ok {
+			return a.covers(leaves...)
+		}
+	case *interval:
+		if b, ok := b.(*interval); ok {
+			return a.equal(b)
+		}
+	case *rrule:
+		if b, ok := b.(*rrule); ok {
+			return a.equal(b)
+		}
+	}
+
+	return false
+}
+
+// intersectionLeaves returns the operands of the given intersection, if all of them are
+// leaf schedules, e.g. the normalized form of the <cron.DialectSystemd> expressions.
+func intersectionLeaves(sp spec) ([]*schedule, bool) {
+	in, ok := sp.(intersection)
+	if !ok {
+		return nil, false
+	}
+
+	leaves := make([]*schedule, 0, len(in))
+
+	for _, operand := range in {
+		leaf, ok := operand.(*schedule)
+		if !ok || leaf.spec != nil {
+			return nil, false
+		}
+
+		leaves = append(leaves, leaf)
+	}
+
+	return leaves, true
+}
+
+// covers reports whether the leaf schedule is executed at all times, at which all the other
+// leaf schedules are executed.
+func (s *schedule) covers(others ...*schedule) bool {
+	a := s.fields
+
+	for _, other := range others {
+		b := other.fields
+
+		if a.once || b.once {
+			return a.once && b.once
+		} else if !sameLocation(a.location, b.location) || !sameCalendar(s.calendar, other.calendar) {
+			return false
+		}
+	}
+
+	for _, get := range timeFields {
+		if !get(a).bits.covers(commonBits(others, get)) {
+			return false
+		}
+	}
+
+	return s.coversDays(others)
+}
+
+// overlaps reports whether the given leaf schedules are executed at common days and at common
+// times of the day, which is required for a common execution. The schedules with different
+// locations or calendars are assumed to overlap.
+func overlaps(leaves []*schedule) bool {
+	bs := make([]*schedule, len(leaves))
+
+	for i, leaf := range leaves {
+		if leaf.fields.once || !sameLocation(leaf.fields.location, leaves[0].fields.location) {
+			return true
+		} else if !sameCalendar(leaf.calendar, leaves[0].calendar) {
+			return true
+		}
+
+		bs[i] = &schedule{fields: leaf.fields}
+	}
+
+	for _, get := range timeFields {
+		if _, ok := commonBits(bs, get).next(0); !ok {
+			return false
+		}
+	}
+
+	var checked [400]bool
+
+	for y, ok := bs[0].fields.nextYear(0); ok; y, ok = bs[0].fields.nextYear(y + 1) {
+		if checked[y%400] || !hasYear(bs, y) {
+			continue
+		}
+
+		checked[y%400] = true
+
+		for m := 1; m <= 12; m++ {
+			if commonDays(bs, y, m) != 0 {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// timeFields contains the getters of the fields of the time of the day.
+var timeFields = []func(fs *fields) *field{
+	func(fs *fields) *field { return fs.millis },
+	func(fs *fields) *field { return fs.seconds },
+	func(fs *fields) *field { return fs.minutes },
+	func(fs *fields) *field { return fs.hours },
+}
+
+// commonBits returns the values of the given field, which are contained in all given schedules.
+func commonBits(schedules []*schedule, get func(fs *fields) *field) bitset {
+	bits := append(bitset{}, get(schedules[0].fields).bits...)
+
+	for _, s := range schedules[1:] {
+		bits.intersect(get(s.fields).bits)
+	}
+
+	return bits
+}
+
+// commonDays returns the days of the given month, at which all given schedules are executed.
+func commonDays(schedules []*schedule, year, month int) uint64 {
+	firstDay := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+
+	days := ^uint64(0)
+	for _, s := range schedules {
+		if !s.fields.month.bits.has(month) {
+			return 0
+		}
+
+		days &= s.getDays(firstDay)
+	}
+
+	return days
+}
+
+// coversDays reports whether the schedule is executed at all days, at which all the other
+// schedules are executed. The days of a year only depend on the position of the year in the
+// Gregorian cycle of 400 years, so that the days are compared once for each position. The
+// calendars are already compared.
+func (s *schedule) coversDays(others []*schedule) bool {
+	a := &schedule{fields: s.fields}
+
+	bs := make([]*schedule, len(others))
+	for i, other := range others {
+		bs[i] = &schedule{fields: other.fields}
+	}
+
+	var checked [400]bool
+	var nonEmpty [400]bool
+
+	for y, ok := bs[0].fields.nextYear(0); ok; y, ok = bs[0].fields.nextYear(y + 1) {
+		if !hasYear(bs, y) {
+			continue
+		}
+
+		cycle := y % 400
+
+		if !checked[cycle] {
+			checked[cycle] = true
+
+			for m := 1; m <= 12; m++ {
+				days := commonDays(bs, y, m)
+				if days == 0 {
+					continue
+				}
+
+				nonEmpty[cycle] = true
+
+				firstDay := time.Date(y, time.Month(m), 1, 0, 0, 0, 0, time.UTC)
+
+				if !a.fields.month.bits.has(m) || a.getDays(firstDay)&days != days {
+					return false
+				}
+			}
+		}
+
+		// The years without any execution do not have to be covered, e.g. the non leap years.
+		if nonEmpty[cycle] && !hasYear([]*schedule{a}, y) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// hasYear reports whether the year fields of all given schedules contain the given year.
+func hasYear(schedules []*schedule, year int) bool {
+	for _, s := range schedules {
+		if y, ok := s.fields.nextYear(year); !ok || y != year {
+			return false
+		}
+	}
+
+	return true
+}
+
+// equal reports whether both rate expressions have the same period. The anchors are only
+// compared if one of them is given explicitly, because the default anchors are the times of
+// parsing, see <cron.WithReferenceTime>.
+func (iv *interval) equal(other *interval) bool {
+	if iv.period != other.period {
+		return false
+	} else if !iv.hasAnchor && !other.hasAnchor {
+		return true
+	}
+
+	return iv.anchor.Equal(other.anchor)
+}
+
+// equal reports whether both recurrence rules have the same properties. The DTSTART is only
+// compared if one of them is given explicitly, because the default one is the day of parsing,
+// but the locations are compared always.
+func (r *rrule) equal(other *rrule) bool {
+	a, b := *r, *other
+
+	if !sameLocation(a.dtstart.Location(), b.dtstart.Location()) || !sameCalendar(a.calendar, b.calendar) {
+		return false
+	} else if (a.hasStart || b.hasStart) && !a.dtstart.Equal(b.dtstart) {
+		return false
+	} else if !a.until.Equal(b.until) || len(a.exdates) != len(b.exdates) {
+		return false
+	}
+
+	for i := range a.exdates {
+		if !a.exdates[i].Equal(b.exdates[i]) {
+			return false
+		}
+	}
+
+	// The remaining properties are compared by value, the cached positions are not compared.
+	a.dtstart, a.until, a.exdates, a.calendar, a.position = time.Time{}, time.Time{}, nil, nil, nil
+	b.dtstart, b.until, b.exdates, b.calendar, b.position = time.Time{}, time.Time{}, nil, nil, nil
+
+	return reflect.DeepEqual(a, b)
+}
+
+// covers reports whether the bitset contains all values of the other bitset.
+func (b bitset) covers(other bitset) bool {
+	for i, word := range other {
+		var own uint64
+		if i < len(b) {
+			own = b[i]
+		}
+
+		if own&word != word {
+			return false
+		}
+	}
+
+	return true
+}
+
+// intersect removes all values from the bitset, which are not in the other bitset.
+func (b bitset) intersect(other bitset) {
+	for i := range b {
+		if i < len(other) {
+			b[i] &= other[i]
+		} else {
+			b[i] = 0
+		}
+	}
+}
+
+// sameLocation reports whether both locations are the same, nil means the local time.
+func sameLocation(l1, l2 *time.Location) bool {
+	if l1 == nil || l2 == nil {
+		return l1 == l2
+	}
+
+	return l1 == l2 || l1.String() == l2.String()
+}
+
+// sameCalendar reports whether both calendars are identical, the calendars with not
+// comparable types are never identical.
+func sameCalendar(c1, c2 Calendar) bool {
+	if c1 == nil || c2 == nil {
+		return c1 == c2
+	} else if !reflect.TypeOf(c1).Comparable() || !reflect.TypeOf(c2).Comparable() {
+		return false
+	}
+
+	return c1 == c2
+}
@@ -0,0 +1,174 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompare_Covers(t *testing.T) {
+	calendar := NewDateCalendar(time.Date(2030, 12, 25, 0, 0, 0, 0, time.UTC))
+
+	type testCase struct {
+		expr1  string
+		expr2  string
+		opts1  []Option
+		opts2  []Option
+		covers bool
+		equal  bool
+	}
+
+	for _, tc := range []testCase{
+		{"@daily", "0 0 0 * * * *", nil, nil, true, true},
+		{"@daily", "0 0 * * *", nil, nil, true, true},
+		{"0 0 0 * * ? *", "0 0 0 ? * * *", nil, nil, true, true},
+		{"0 0 0 * * ? *", "0 0 0 1-31 * ? *", nil, nil, true, true},
+		{"0 0 0 ? * MON-FRI *", "0 0 0 ? * 1-5 *", nil, nil, true, true},
+		{"0 0 0 ? * 7 *", "0 0 0 ? * 0 *", nil, nil, true, true},
+		{"0 */15 * * * ? *", "0 0,30 * * * ? *", nil, nil, true, false},
+		{"0 0,30 * * * ? *", "0 */15 * * * ? *", nil, nil, false, false},
+		{"0 0 9 * * ? *", "0 0 9 L * ? *", nil, nil, true, false},
+		{"0 0 9 L * ? *", "0 0 9 28-31 * ? *", nil, nil, false, false},
+		{"0 0 9 ? * MON *", "0 0 9 ? * MON#2 *", nil, nil, true, false},
+		{"0 0 9 ? * MON#1,MON#2,MON#3,MON#4,MON#5 *", "0 0 9 ? * MON *", nil, nil, true, true},
+		{"0 0 9 1-7 * ? *", "0 0 9 ? * 2#1 *", nil, nil, true, false},
+		{"0 0 9 ? * * 2020-2030", "0 0 9 ? * * 2025", nil, nil, true, false},
+		{"0 0 9 29 2 ? *", "0 0 9 29 2 ? 2021-2024", nil, nil, true, false},
+		{"0 0 9 29 2 ? 2024", "0 0 9 29 2 ? 2021-2027", nil, nil, true, true},
+		{"0 0 9 ? * * 2020/4", "0 0 9 ? * * 2024,2028", nil, nil, true, false},
+		{"0 0 9 * * ? *", "0 0 9 * * ? *", nil, []Option{WithLocation(time.UTC)}, false, false},
+		{"0 0 9 * * ? *", "0 0 9 * * ? *", []Option{WithCalendar(calendar)}, nil, false, false},
+		{"0 0 9 * * ? *", "0 0 9 * * ? *", nil, []Option{WithCalendar(calendar)}, false, false},
+		{"0 0 9 * * ? *", "0 0 9 * * ? *", []Option{WithCalendar(calendar)}, []Option{WithCalendar(calendar)}, true, true},
+		{"@reboot", "@reboot", nil, nil, true, true},
+		{"@reboot", "@daily", nil, nil, false, false},
+		{"0 0 9 * * ? * | 0 0 18 * * ? *", "0 0 9,18 * * ? *", nil, nil, false, false},
+		{"0 0 9,18 * * ? *", "0 0 9 * * ? * | 0 0 18 * * ? *", nil, nil, true, false},
+		{"0 0 9 * * ? * | 0 0 18 * * ? *", "0 0 18 * * ? * | 0 0 9 * * ? *", nil, nil, true, true},
+		{"0 0 9 ? * MON-FRI *", "0 0 9 ? * MON-FRI * ! 0 0 9 25 12 ? *", nil, nil, true, false},
+		{"0 0 9 * * ? *", "0 0 9 ? * MON-FRI * & 0 0 9 1 * ? *", nil, nil, true, false},
+		{"rate(5 minutes)", "rate(5 minutes)", []Option{WithDialect(DialectEventBridge)}, []Option{WithDialect(DialectEventBridge)}, true, true},
+		{"rate(5 minutes)", "rate(10 minutes)", []Option{WithDialect(DialectEventBridge)}, []Option{WithDialect(DialectEventBridge)}, false, false},
+	} {
+		s1, err := Parse(tc.expr1, tc.opts1...)
+		if err != nil {
+			t.Fatalf("'%s': unexpected error '%s'", tc.expr1, err)
+		}
+
+		s2, err := Parse(tc.expr2, tc.opts2...)
+		if err != nil {
+			t.Fatalf("'%s': unexpected error '%s'", tc.expr2, err)
+		}
+
+		if got := s1.Covers(s2); got != tc.covers {
+			t.Errorf("'%s' covers '%s': expected '%t', got '%t'", tc.expr1, tc.expr2, tc.covers, got)
+		}
+
+		if got := s1.Equal(s2); got != tc.equal {
+			t.Errorf("'%s' equals '%s': expected '%t', got '%t'", tc.expr1, tc.expr2, tc.equal, got)
+		}
+	}
+}
+
+func TestCompare_Covers_Anchors(t *testing.T) {
+	type testCase struct {
+		expr1 string
+		expr2 string
+		opts  []Option
+		later []Option // Options of the second schedule, which is parsed two days later.
+		exp   bool
+	}
+
+	eb, rr := WithDialect(DialectEventBridge), WithDialect(DialectRRule)
+	ref := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range []testCase{
+		{"rate(5 minutes)", "rate(5 minutes)", []Option{eb}, nil, true},
+		{"rate(5 minutes)", "rate(5 minutes)", []Option{eb, WithReferenceTime(ref)}, []Option{WithReferenceTime(ref)}, true},
+		{"rate(5 minutes)", "rate(5 minutes)", []Option{eb, WithReferenceTime(ref)}, []Option{WithReferenceTime(ref.Add(time.Minute))}, false},
+		{"rate(5 minutes)", "rate(1 hour)", []Option{eb}, nil, false},
+		{"FREQ=DAILY;INTERVAL=2", "FREQ=DAILY;INTERVAL=2", []Option{rr}, nil, true},
+		{"FREQ=DAILY;INTERVAL=2", "FREQ=DAILY;INTERVAL=3", []Option{rr}, nil, false},
+		{"FREQ=DAILY;BYHOUR=9", "FREQ=DAILY;BYHOUR=9", []Option{rr, WithLocation(time.UTC)}, []Option{WithLocation(time.FixedZone("UTC+1", 3600))}, false},
+		{"DTSTART:20230101T090000Z RRULE:FREQ=DAILY", "DTSTART:20230101T090000Z RRULE:FREQ=DAILY", []Option{rr}, nil, true},
+		{"DTSTART:20230101T090000Z RRULE:FREQ=DAILY", "DTSTART:20230102T090000Z RRULE:FREQ=DAILY", []Option{rr}, nil, false},
+		{"RRULE:FREQ=DAILY EXDATE:20230105T000000Z", "RRULE:FREQ=DAILY", []Option{rr}, nil, false},
+	} {
+		o1 := newOptions(tc.opts)
+
+		o2 := newOptions(append(append([]Option{}, tc.opts...), tc.later...))
+		if !o2.hasNow {
+			o2.now = o2.now.Add(48 * time.Hour)
+		}
+
+		sp1, err := parseSpec(tc.expr1, o1)
+		if err != nil {
+			t.Fatalf("'%s': unexpected error '%s'", tc.expr1, err)
+		}
+
+		sp2, err := parseSpec(tc.expr2, o2)
+		if err != nil {
+			t.Fatalf("'%s': unexpected error '%s'", tc.expr2, err)
+		}
+
+		s1, s2 := &Schedule{expression: tc.expr1, spec: sp1}, &Schedule{expression: tc.expr2, spec: sp2}
+		if got := s1.Equal(s2); got != tc.exp {
+			t.Errorf("'%s' equals '%s': expected '%t', got '%t'", tc.expr1, tc.expr2, tc.exp, got)
+		}
+	}
+}
+
+func TestCompare_bitsetCovers(t *testing.T) {
+	type testCase struct {
+		b1  bitset
+		b2  bitset
+		exp bool
+	}
+
+	for _, tc := range []testCase{
+		{bitset{0b111}, bitset{0b101}, true},
+		{bitset{0b101}, bitset{0b111}, false},
+		{bitset{0b1}, bitset{0b1, 0b1}, false},
+		{bitset{0b1, 0b1}, bitset{0b1}, true},
+		{bitset{0b1}, bitset{0b1, 0}, true},
+	} {
+		if got := tc.b1.covers(tc.b2); got != tc.exp {
+			t.Errorf("'%v' covers '%v': expected '%t', got '%t'", tc.b1, tc.b2, tc.exp, got)
+		}
+	}
+}
+
+func TestCompare_overlaps(t *testing.T) {
+	type testCase struct {
+		expr string
+		exp  bool
+	}
+
+	for _, tc := range []testCase{
+		{"0 0 0 ? * MON * & 0 0 0 ? * TUE *", false},
+		{"0 0 0 ? * MON * & 0 0 0 13 * ? *", true},
+		{"0 0 9 * * ? * & 0 0 18 * * ? *", false},
+		{"0 0 9 29 2 ? * & 0 0 9 * * ? 2021-2023", false},
+		{"0 0 9 29 2 ? * & 0 0 9 * * ? 2021-2024", true},
+		{"0 0 9 31 * ? * & 0 0 9 * 4,6,9,11 ? *", false},
+		{"0 0 0 ? * MON * & 0 0 0 ? * MON * & 0 0 0 ? * TUE *", false},
+	} {
+		sp, err := parseSpec(tc.expr, testOptions)
+		if err != nil {
+			t.Fatalf("'%s': unexpected error '%s'", tc.expr, err)
+		}
+
+		leaves, ok := intersectionLeaves(sp)
+		if !ok {
+			t.Fatalf("'%s': expected an intersection of leaf schedules", tc.expr)
+		}
+
+		if got := overlaps(leaves); got != tc.exp {
+			t.Errorf("'%s': expected '%t', got '%t'", tc.expr, tc.exp, got)
+		}
+
+		next, state := sp.next(testScheduleTime)
+		if got := state == StateFound; got != tc.exp {
+			t.Errorf("'%s': expected '%t', got '%s' (%s)", tc.expr, tc.exp, next, state)
+		}
+	}
+}
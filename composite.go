@@ -0,0 +1,473 @@
+// Copyright 2022 Alex Schneider. All rights reserved.
+
+package cron
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+const (
+	opUnion     = '|'
+	opIntersect = '&'
+	opExcept    = '!'
+)
+
+// maxCompositeIterations limits the search for the next execution of composite
+// schedules whose operands do not match at the same time.
+const maxCompositeIterations = 1000000
+
+// resolution is the smallest time unit of the cron expressions.
+const resolution = time.Millisecond
+
+// spec is implemented by all parsed expressions which are able to
+// calculate the next execution time.
+type spec interface {
+	next(referenceTime time.Time) (time.Time, State)
+}
+
+type union []spec
+
+type intersection []spec
+
+type exception struct {
+	spec     spec
+	excluded spec
+}
+
+/* ==================================================================================================== */
+
+// Union returns a new <cron.Schedule> that is executed at the times of any of the given schedules.
+// The zero schedules are skipped, because they are never executed.
+func Union(schedules ...*Schedule) *Schedule {
+	var operands []*Schedule
+
+	for _, s := range schedules {
+		if !s.IsZero() {
+			operands = append(operands, s)
+		}
+	}
+
+	if len(operands) == 0 {
+		return &Schedule{}
+	}
+
+	u := make(union, len(operands))
+
+	for i, s := range operands {
+		u[i] = s.spec
+	}
+
+	return &Schedule{expression: joinExpressions(operands, opUnion), spec: u, native: allNative(operands)}
+}
+
+// Intersect returns a new <cron.Schedule> that is executed only at the times
+// shared by all of the given schedules. The zero schedule is returned if any
+// of the given schedules is zero or no schedule is given.
+func Intersect(schedules ...*Schedule) *Schedule {
+	if len(schedules) == 0 {
+		return &Schedule{}
+	}
+
+	in := make(intersection, len(schedules))
+
+	for i, s := range schedules {
+		if s.IsZero() {
+			return &Schedule{}
+		}
+
+		in[i] = s.spec
+	}
+
+	return &Schedule{expression: joinExpressions(schedules, opIntersect), spec: in, native: allNative(schedules)}
+}
+
+// Except returns a new <cron.Schedule> that is executed at the times of the given
+// schedule excepting the times of the excluded schedule. The zero schedule is returned
+// if the given schedule is zero, and a copy of it if the excluded schedule is zero.
+func Except(schedule, excluded *Schedule) *Schedule {
+	if schedule.IsZero() {
+		return &Schedule{}
+	} else if excluded.IsZero() {
+		s := *schedule
+
+		return &s
+	}
+
+	return &Schedule{
+		expression: joinExpressions([]*Schedule{schedule, excluded}, opExcept),
+		spec:       &exception{spec: schedule.spec, excluded: excluded.spec},
+		native:     allNative([]*Schedule{schedule, excluded}),
+	}
+}
+
+// allNative reports whether the expressions of all given schedules use the native syntax.
+func allNative(schedules []*Schedule) bool {
+	for _, s := range schedules {
+		if !s.native {
+			return false
+		}
+	}
+
+	return true
+}
+
+func joinExpressions(schedules []*Schedule, op rune) string {
+	parts := make([]string, len(schedules))
+
+	for i, s := range schedules {
+		if isCompositeExpression(s.expression) {
+			parts[i] = "(" + s.expression + ")"
+		} else {
+			parts[i] = s.expression
+		}
+	}
+
+	return strings.Join(parts, " "+string(op)+" ")
+}
+
+/* ==================================================================================================== */
+
+func (u union) next(referenceTime time.Time) (time.Time, State) {
+	var best time.Time
+
+	for _, sp := range u {
+		t, state := sp.next(referenceTime)
+		if state == StateZeroTime {
+			return t, state
+		}
+
+		if state == StateFound && (best.IsZero() || t.Before(best)) {
+			best = t
+		}
+	}
+
+	if best.IsZero() {
+		return time.Time{}, StateNoMatches
+	}
+
+	return best, StateFound
+}
+
+func (in intersection) next(referenceTime time.Time) (time.Time, State) {
+	// The operands, which are never executed at the same time, are not searched at all.
+	if leaves, ok := intersectionLeaves(in); ok && !referenceTime.IsZero() && !overlaps(leaves) {
+		return time.Time{}, StateNoMatches
+	}
+
+	candidate := referenceTime
+
+	for i := 0; i < maxCompositeIterations; i++ {
+		var latest time.Time
+
+		same := true
+
+		for j, sp := range in {
+			t, state := sp.next(candidate)
+			if state != StateFound {
+				return time.Time{}, state
+			}
+
+			if j == 0 {
+				latest = t
+			} else if !t.Equal(latest) {
+				same = false
+
+				if t.After(latest) {
+					latest = t
+				}
+			}
+		}
+
+		if same {
+			return latest, StateFound
+		}
+
+		// All operands have to be checked from the latest time on.
+		candidate = latest.Add(-resolution)
+	}
+
+	return time.Time{}, StateNoMatches
+}
+
+func (e *exception) next(referenceTime time.Time) (time.Time, State) {
+	t, state := e.spec.next(referenceTime)
+	if state == StateFound && covers(e.excluded, e.spec) {
+		return time.Time{}, StateNoMatches
+	}
+
+	// The excluded times only have to be consecutive at the times of the leaves of the spec.
+	var leaves []*schedule
+	if isScheduleComposite(e.spec) {
+		leaves = leafSchedules(e.spec)
+	}
+
+	for i := 0; i < maxCompositeIterations; i++ {
+		if state != StateFound || !matchesSpec(e.excluded, t) {
+			return t, state
+		}
+
+		// The consecutive excluded times are skipped at once, e.g. the whole hour of `* * 2 * * ?`.
+		t, state = e.spec.next(matchedUntil(e.excluded, t, leaves))
+	}
+
+	return time.Time{}, StateNoMatches
+}
+
+// matchesSpec reports whether the given spec is executed at the given time. The composites
+// and the schedules are evaluated directly, the other specs are evaluated by their next time.
+func matchesSpec(sp spec, t time.Time) bool {
+	switch sp := sp.(type) {
+	case *schedule:
+		if sp.spec != nil {
+			return matchesSpec(sp.spec, t)
+		}
+
+		return sp.matches(t)
+	case union:
+		for _, operand := range sp {
+			if matchesSpec(operand, t) {
+				return true
+			}
+		}
+
+		return false
+	case intersection:
+		for _, operand := range sp {
+			if !matchesSpec(operand, t) {
+				return false
+			}
+		}
+
+		return true
+	case *exception:
+		return matchesSpec(sp.spec, t) && !matchesSpec(sp.excluded, t)
+	}
+
+	next, state := sp.next(t.Add(-resolution))
+
+	return state == StateFound && next.Equal(t)
+}
+
+// matchedUntil returns the last time of the consecutive executions of the given spec, which
+// start at the given matching time, limited to the times of the given leaf schedules. See
+// <cron.schedule.matchedUntil> for the leaf schedules, the given time is returned for the
+// specs other than the composites and the schedules.
+func matchedUntil(sp spec, t time.Time, others []*schedule) time.Time {
+	last := t
+
+	switch sp := sp.(type) {
+	case *schedule:
+		if sp.spec != nil {
+			return matchedUntil(sp.spec, t, others)
+		}
+
+		return sp.matchedUntil(t, others)
+	case union:
+		// The union is executed until the latest end of its matching operands at least.
+		for _, operand := range sp {
+			if matchesSpec(operand, t) {
+				if until := matchedUntil(operand, t, others); until.After(last) {
+					last = until
+				}
+			}
+		}
+	case intersection:
+		for i, operand := range sp {
+			if until := matchedUntil(operand, t, others); i == 0 || until.Before(last) {
+				last = until
+			}
+		}
+	}
+
+	return last
+}
+
+// isScheduleComposite reports whether the given spec consists of composites and schedules only.
+func isScheduleComposite(sp spec) bool {
+	_, err := walkSpec(sp, func(*schedule) string { return "" }, "", "", "")
+
+	return err == nil
+}
+
+/* ==================================================================================================== */
+
+// parseSpec parses the given expression, which can be a composite of
+// several cron expressions combined by the following operators:
+//   - `|` (union), e.g. `0 */15 * * MON-FRI | 0 0 * * SAT,SUN`.
+//   - `&` (intersection), binds stronger than `|` and `!`.
+//   - `!` (exception), e.g. `0 */15 * * * ! 0 * 2 * *`.
+//
+// Parentheses can be used to group the operands.
+func parseSpec(expression string, o *options) (spec, error) {
+	switch o.dialect {
+	case DialectEventBridge:
+		return parseEventBridgeSpec(expression, o)
+	case DialectRRule:
+		return parseRRule(expression, o)
+	}
+
+	if !isCompositeExpression(expression) {
+		return newLeafSpec(expression, o)
+	} else if o.dialect == DialectVixie {
+		return nil, fmt.Errorf("composite expressions are not supported in Vixie expressions")
+	}
+
+	p := &compositeParser{expression: expression, options: o}
+
+	sp, err := p.parseUnion()
+	if err != nil {
+		return nil, err
+	} else if p.pos < len(p.expression) {
+		return nil, p.error()
+	}
+
+	return sp, nil
+}
+
+func newLeafSpec(expression string, o *options) (*schedule, error) {
+	fields, err := getFields(expression, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schedule{fields: fields, calendar: o.calendar}, nil
+}
+
+func isCompositeExpression(expression string) bool {
+	return strings.ContainsAny(expression, "|&!()")
+}
+
+type compositeParser struct {
+	expression string
+	options    *options
+	pos        int
+}
+
+func (p *compositeParser) parseUnion() (spec, error) {
+	sp, err := p.parseIntersection()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch p.peek() {
+		case opUnion:
+			p.pos++
+
+			operand, err := p.parseIntersection()
+			if err != nil {
+				return nil, err
+			}
+
+			if u, ok := sp.(union); ok {
+				sp = append(u, operand)
+			} else {
+				sp = union{sp, operand}
+			}
+		case opExcept:
+			p.pos++
+
+			excluded, err := p.parseIntersection()
+			if err != nil {
+				return nil, err
+			}
+
+			sp = &exception{spec: sp, excluded: excluded}
+		default:
+			return sp, nil
+		}
+	}
+}
+
+func (p *compositeParser) parseIntersection() (spec, error) {
+	sp, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek() == opIntersect {
+		p.pos++
+
+		operand, err := p.parseOperand()
+		if err != nil {
+			return nil, err
+		}
+
+		if in, ok := sp.(intersection); ok {
+			sp = append(in, operand)
+		} else {
+			sp = intersection{sp, operand}
+		}
+	}
+
+	return sp, nil
+}
+
+func (p *compositeParser) parseOperand() (spec, error) {
+	if p.peek() == '(' {
+		p.pos++
+
+		sp, err := p.parseUnion()
+		if err != nil {
+			return nil, err
+		} else if p.peek() != ')' {
+			return nil, p.error()
+		}
+
+		p.pos++
+
+		return sp, nil
+	}
+
+	start := p.pos
+
+	for p.pos < len(p.expression) && !strings.ContainsRune("|&!()", rune(p.expression[p.pos])) {
+		p.pos++
+	}
+
+	expression := strings.TrimSpace(p.expression[start:p.pos])
+	if expression == "" {
+		return nil, p.error()
+	}
+
+	offset := start + strings.Index(p.expression[start:p.pos], expression)
+
+	leaf, err := newLeafSpec(expression, p.options)
+	if err != nil {
+		// The position is relative to the whole composite expression.
+		if pe, ok := err.(*ParseError); ok && pe.Position >= 0 {
+			return nil, &ParseError{Expression: p.expression, Position: offset + pe.Position, Err: pe.Err}
+		}
+
+		return nil, err
+	} else if leaf.fields.once {
+		return nil, fmt.Errorf("the macro '@reboot' cannot be combined with other expressions")
+	}
+
+	leaf.offset = offset
+
+	return leaf, nil
+}
+
+// peek returns the next non-whitespace character without consuming it.
+func (p *compositeParser) peek() byte {
+	for p.pos < len(p.expression) && (p.expression[p.pos] == ' ' || p.expression[p.pos] == '\t') {
+		p.pos++
+	}
+
+	if p.pos == len(p.expression) {
+		return 0
+	}
+
+	return p.expression[p.pos]
+}
+
+func (p *compositeParser) error() error {
+	return &ParseError{
+		Expression: p.expression,
+		Position:   p.pos,
+		Err:        fmt.Errorf("invalid composite expression given '%s' at position %d", p.expression, p.pos),
+	}
+}
@@ -0,0 +1,191 @@
+package cron
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestComposite_parseSpec(t *testing.T) {
+	type testCase struct {
+		expr    string
+		state   State
+		refTime time.Time
+		expTime time.Time
+	}
+
+	// 2022-12-30 is a Friday.
+	for _, tc := range []testCase{
+		{
+			"0 */15 * ? * MON-FRI | 0 0 * ? * SAT,SUN",
+			StateFound,
+			time.Date(2022, 12, 30, 23, 50, 0, 0, startupTime.Location()),
+			time.Date(2022, 12, 31, 0, 0, 0, 0, startupTime.Location()),
+		},
+		{
+			"0 0 * ? * MON-FRI & 0 0 12 * * ?",
+			StateFound,
+			time.Date(2022, 12, 30, 23, 50, 0, 0, startupTime.Location()),
+			time.Date(2023, 1, 2, 12, 0, 0, 0, startupTime.Location()),
+		},
+		{
+			"0 0 * * * ? ! 0 0 2-3 * * ?",
+			StateFound,
+			time.Date(2022, 12, 30, 1, 30, 0, 0, startupTime.Location()),
+			time.Date(2022, 12, 30, 4, 0, 0, 0, startupTime.Location()),
+		},
+		{
+			"(0 0 * * * ? | 0 30 * * * ?) ! 0 * 2-3 * * ?",
+			StateFound,
+			time.Date(2022, 12, 30, 1, 30, 0, 0, startupTime.Location()),
+			time.Date(2022, 12, 30, 4, 0, 0, 0, startupTime.Location()),
+		},
+		{
+			"0 0 0 1 1 ? 2020 | 0 0 0 1 1 ? 2021",
+			StateNoMatches,
+			testScheduleTime,
+			time.Time{},
+		},
+		{
+			"0 0 * * * ? 2023 & 0 30 * * * ? 2023",
+			StateNoMatches,
+			testScheduleTime,
+			time.Time{},
+		},
+	} {
+		sp, err := parseSpec(tc.expr, newOptions(nil))
+		if err != nil {
+			t.Fatalf("'%s': unexpected error: %#v", tc.expr, err)
+		}
+
+		tm, state := sp.next(tc.refTime)
+		if state != tc.state {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.state, state)
+		}
+
+		if tm.String() != tc.expTime.String() {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.expTime.String(), tm.String())
+		}
+	}
+}
+
+func TestComposite_parseSpec_Error(t *testing.T) {
+	type testCase struct {
+		expr string
+		err  string
+	}
+
+	for _, tc := range []testCase{
+		{"(0 0 * * *", `invalid composite expression given '(0 0 * * *' at position 10`},
+		{"0 0 * * * |", `invalid composite expression given '0 0 * * * |' at position 11`},
+		{"0 0 * * *) | 0 0 * * *", `invalid composite expression given '0 0 * * *) | 0 0 * * *' at position 9`},
+		{"@reboot | @daily", `the macro '@reboot' cannot be combined with other expressions`},
+		{"X | @daily", `invalid expression given 'X'`},
+	} {
+		sp, err := parseSpec(tc.expr, newOptions(nil))
+		if sp != nil {
+			t.Errorf("'%s': expected '%#v', got '%#v'", tc.expr, nil, sp)
+		}
+
+		if eerr := fmt.Sprintf("%s", err); tc.err != eerr {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.err, eerr)
+		}
+	}
+}
+
+func TestComposite_Schedules(t *testing.T) {
+	weekdays, _ := Parse("0 */15 * ? * MON-FRI")
+	weekends, _ := Parse("0 0 * ? * SAT,SUN")
+	nights, _ := Parse("0 * 2 * * ?")
+
+	s := Except(Union(weekdays, weekends), nights)
+
+	if exp := "(0 */15 * ? * MON-FRI | 0 0 * ? * SAT,SUN) ! 0 * 2 * * ?"; s.String() != exp {
+		t.Errorf("expected '%s', got '%s'", exp, s.String())
+	}
+
+	got := s.Next(time.Date(2022, 12, 30, 1, 50, 0, 0, startupTime.Location()))
+	if exp := time.Date(2022, 12, 30, 3, 0, 0, 0, startupTime.Location()); got.String() != exp.String() {
+		t.Errorf("expected '%s', got '%s'", exp, got)
+	}
+
+	s = Intersect(weekdays, nights)
+
+	got = s.Next(time.Date(2022, 12, 30, 1, 50, 0, 0, startupTime.Location()))
+	if exp := time.Date(2022, 12, 30, 2, 0, 0, 0, startupTime.Location()); got.String() != exp.String() {
+		t.Errorf("expected '%s', got '%s'", exp, got)
+	}
+
+}
+
+func TestComposite_Schedules_Zero(t *testing.T) {
+	type testCase struct {
+		s   *Schedule
+		exp string
+	}
+
+	daily, _ := Parse("@daily")
+	ref := time.Date(2022, 12, 30, 1, 50, 0, 0, time.UTC)
+
+	for i, tc := range []testCase{
+		{Union(), ""},
+		{Union(nil, &Schedule{}), ""},
+		{Union(&Schedule{}, daily, nil), "@daily"},
+		{Intersect(), ""},
+		{Intersect(daily, &Schedule{}), ""},
+		{Intersect(nil, daily), ""},
+		{Except(&Schedule{}, daily), ""},
+		{Except(nil, daily), ""},
+		{Except(daily, &Schedule{}), "@daily"},
+		{Except(daily, nil), "@daily"},
+	} {
+		if tc.s.String() != tc.exp {
+			t.Errorf("%d: expected '%s', got '%s'", i, tc.exp, tc.s.String())
+		}
+
+		exp := time.Time{}
+		if tc.exp != "" {
+			exp = daily.Next(ref)
+		}
+
+		if got := tc.s.Next(ref); !got.Equal(exp) {
+			t.Errorf("%d: expected '%s', got '%s'", i, exp, got)
+		}
+	}
+}
+
+func TestComposite_exception_next(t *testing.T) {
+	type testCase struct {
+		expr string
+		loc  *time.Location
+		exp  time.Time
+	}
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref := time.Date(2023, 1, 2, 2, 0, 0, 0, time.UTC)
+
+	for _, tc := range []testCase{
+		{"* * * * * * * * ! * * * 2 * * * *", time.UTC, time.Date(2023, 1, 2, 3, 0, 0, 0, time.UTC)},
+		{"* * * * * * * * ! * * * * * * ? * & * * * * 2 * ? *", time.UTC, time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{"* * * * * * * * ! (* * * 2 * * * * | * * * 3 * * * *)", time.UTC, time.Date(2023, 1, 2, 4, 0, 0, 0, time.UTC)},
+		{"0 * * * * ? * ! * * 2-4 * * ? *", time.UTC, time.Date(2023, 1, 2, 5, 0, 0, 0, time.UTC)},
+		{"* * * * * ? * ! * * * * * ? 2023", time.UTC, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{"* * * * * ? * ! * * * * * ? 2023", berlin, time.Date(2023, 12, 31, 23, 0, 0, 0, time.UTC)},
+		{"*/2 * * * * * * * ! 0-998/2 * * 2 * * * *", time.UTC, time.Date(2023, 1, 2, 3, 0, 0, 0, time.UTC)},
+		{"*/2 * * * * * * * ! 0-998/2 * * * * * * *", time.UTC, time.Time{}},
+		{"0 0 12 * * ? * ! 0 0 12 ? * * *", time.UTC, time.Time{}},
+	} {
+		s, err := Parse(tc.expr, WithLocation(tc.loc))
+		if err != nil {
+			t.Fatalf("'%s': unexpected error '%s'", tc.expr, err)
+		}
+
+		if got := s.Next(ref); !got.Equal(tc.exp) {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.exp, got)
+		}
+	}
+}
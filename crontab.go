@@ -0,0 +1,221 @@
+// Copyright 2022 Alex Schneider. All rights reserved.
+
+package cron
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var reCrontabEnv = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)\s*=\s*(.*)$`)
+
+// crontabFields contains the number of the fields of the expressions in the crontab files for
+// the supported dialects. The Quartz expressions require all fields including the year.
+var crontabFields = map[Dialect]int{
+	DialectNative: 5,
+	DialectQuartz: 7,
+	DialectVixie:  5,
+}
+
+// CrontabEntry represents a single cronjob of a crontab file.
+type CrontabEntry struct {
+	// Line contains the number of the line in the crontab file starting at 1.
+	Line int
+
+	// Expression contains the cron expression of the entry, e.g. `*/5 * * * *` or `@daily`.
+	Expression string
+
+	// User contains the user of the entry, which is only set in the system crontabs.
+	User string
+
+	// Command contains the command of the entry as written in the crontab file.
+	Command string
+
+	// Env contains the environment assignments which precede the entry, e.g. `SHELL` or `MAILTO`.
+	Env map[string]string
+
+	// Schedule contains the parsed expression. The `CRON_TZ` assignment defines its location.
+	Schedule *Schedule
+}
+
+// CrontabError represents an invalid line of a crontab file.
+type CrontabError struct {
+	Line int
+	Err  error
+}
+
+// CrontabErrors contains all invalid lines of a crontab file.
+type CrontabErrors []*CrontabError
+
+/* ==================================================================================================== */
+
+// Error implements the <error> interface.
+func (e *CrontabError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *CrontabError) Unwrap() error {
+	return e.Err
+}
+
+// Error implements the <error> interface.
+func (e CrontabErrors) Error() string {
+	messages := make([]string, len(e))
+
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return strings.Join(messages, "\n")
+}
+
+/* ==================================================================================================== */
+
+// LoadCrontab reads the crontab file with the given name and returns its entries.
+// See <cron.ParseCrontab> for the file format.
+func LoadCrontab(name string, opts ...Option) ([]*CrontabEntry, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ParseCrontab(f, opts...)
+}
+
+// ParseCrontab returns the entries of the given crontab file.
+//
+// Each line contains either an environment assignment like `MAILTO=root`, or an entry with a
+// 5 fields expression (7 fields with the <cron.DialectQuartz>) or a macro followed by the command. The system crontabs have the user
+// column between the expression and the command, see <cron.WithUserColumn>. Blank lines and
+// lines starting with `#` are ignored. The `CRON_TZ` assignment defines the location of the
+// following entries.
+//
+// The expressions are parsed with the <cron.DialectVixie> by default, the other dialects than the
+// <cron.DialectNative> and the <cron.DialectQuartz> are not supported. The invalid lines do not
+// stop the parsing, the valid entries are returned together with the <cron.CrontabErrors>.
+func ParseCrontab(r io.Reader, opts ...Option) ([]*CrontabEntry, error) {
+	opts = append([]Option{WithDialect(DialectVixie)}, opts...)
+	o := newOptions(opts)
+
+	fieldsCount, ok := crontabFields[o.dialect]
+	if !ok {
+		return nil, fmt.Errorf("the dialect '%s' is not supported in crontab files", o.dialect)
+	}
+
+	var entries []*CrontabEntry
+	var errs CrontabErrors
+	var location *time.Location
+	var line int
+
+	env := map[string]string{}
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		line++
+
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		if matches := reCrontabEnv.FindStringSubmatch(text); len(matches) == 3 {
+			name, value := matches[1], unquoteCrontabValue(matches[2])
+
+			if name == "CRON_TZ" {
+				loc, err := time.LoadLocation(value)
+				if err != nil {
+					errs = append(errs, &CrontabError{Line: line, Err: fmt.Errorf("invalid timezone given '%s'", value)})
+
+					continue
+				}
+
+				location = loc
+			}
+
+			env[name] = value
+
+			continue
+		}
+
+		entry, err := parseCrontabEntry(text, fieldsCount, o.userColumn)
+		if err == nil {
+			entryOpts := opts
+			if location != nil {
+				entryOpts = append(entryOpts[:len(entryOpts):len(entryOpts)], WithLocation(location))
+			}
+
+			entry.Schedule, err = Parse(entry.Expression, entryOpts...)
+		}
+
+		if err != nil {
+			errs = append(errs, &CrontabError{Line: line, Err: err})
+
+			continue
+		}
+
+		entry.Line = line
+		entry.Env = make(map[string]string, len(env))
+
+		for k, v := range env {
+			entry.Env[k] = v
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(errs) > 0 {
+		return entries, errs
+	}
+
+	return entries, nil
+}
+
+func parseCrontabEntry(text string, fieldsCount int, userColumn bool) (*CrontabEntry, error) {
+	indexes := reFieldsMatcher.FindAllStringIndex(text, -1)
+
+	// The macros like `@daily` replace all fields.
+	if strings.HasPrefix(text, "@") {
+		fieldsCount = 1
+	}
+
+	columns := fieldsCount + 1
+	if userColumn {
+		columns++
+	}
+
+	if len(indexes) < columns {
+		return nil, fmt.Errorf("missing command in crontab entry given '%s'", text)
+	}
+
+	entry := &CrontabEntry{
+		Expression: text[:indexes[fieldsCount-1][1]],
+		Command:    text[indexes[columns-1][0]:],
+	}
+
+	if userColumn {
+		entry.User = text[indexes[fieldsCount][0]:indexes[fieldsCount][1]]
+	}
+
+	return entry, nil
+}
+
+func unquoteCrontabValue(value string) string {
+	value = strings.TrimSpace(value)
+
+	if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+		return value[1 : len(value)-1]
+	}
+
+	return value
+}
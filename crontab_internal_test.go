@@ -0,0 +1,165 @@
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCrontab_ParseCrontab(t *testing.T) {
+	data := `# m h dom mon dow command
+SHELL=/bin/bash
+MAILTO="admin@example.com"
+
+*/5 * * * * /usr/bin/backup --quick   >/dev/null 2>&1
+@reboot /usr/bin/startup
+0 0 * * L /usr/bin/invalid
+CRON_TZ=Europe/Berlin
+30 9 * * MON-FRI echo "hello world"
+0 12 * *
+CRON_TZ=Mars/Base
+`
+
+	entries, err := ParseCrontab(strings.NewReader(data))
+
+	var errs CrontabErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("expected '%T', got '%#v'", errs, err)
+	}
+
+	expErr := "line 7: the special character 'L' is not supported in Vixie expressions\n" +
+		"line 10: missing command in crontab entry given '0 12 * *'\n" +
+		"line 11: invalid timezone given 'Mars/Base'"
+	if err.Error() != expErr {
+		t.Errorf("expected '%s', got '%s'", expErr, err)
+	}
+
+	type testCase struct {
+		line    int
+		expr    string
+		command string
+		env     string
+	}
+
+	exp := []testCase{
+		{5, "*/5 * * * *", "/usr/bin/backup --quick   >/dev/null 2>&1", "map[MAILTO:admin@example.com SHELL:/bin/bash]"},
+		{6, "@reboot", "/usr/bin/startup", "map[MAILTO:admin@example.com SHELL:/bin/bash]"},
+		{9, "30 9 * * MON-FRI", `echo "hello world"`, "map[CRON_TZ:Europe/Berlin MAILTO:admin@example.com SHELL:/bin/bash]"},
+	}
+
+	if len(entries) != len(exp) {
+		t.Fatalf("expected '%d', got '%d'", len(exp), len(entries))
+	}
+
+	for i, tc := range exp {
+		got := entries[i]
+
+		if got.Line != tc.line {
+			t.Errorf("'%s': expected '%d', got '%d'", tc.expr, tc.line, got.Line)
+		}
+
+		if got.Expression != tc.expr {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.expr, got.Expression)
+		}
+
+		if got.Command != tc.command {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.command, got.Command)
+		}
+
+		if env := fmt.Sprint(got.Env); env != tc.env {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.env, env)
+		}
+
+		if got.User != "" {
+			t.Errorf("'%s': expected '', got '%s'", tc.expr, got.User)
+		}
+	}
+
+	// The CRON_TZ defines the location of the following entries.
+	berlin, _ := time.LoadLocation("Europe/Berlin")
+
+	got := entries[2].Schedule.Next(time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC))
+	if exp := time.Date(2023, 1, 2, 9, 30, 0, 0, berlin); got.String() != exp.String() {
+		t.Errorf("expected '%s', got '%s'", exp, got)
+	}
+}
+
+func TestCrontab_WithUserColumn(t *testing.T) {
+	data := `17 * * * * root cd / && run-parts --report /etc/cron.hourly
+@daily www-data /usr/bin/cleanup
+0 0 * * * root
+`
+
+	entries, err := ParseCrontab(strings.NewReader(data), WithUserColumn())
+	if exp := "line 3: missing command in crontab entry given '0 0 * * * root'"; fmt.Sprint(err) != exp {
+		t.Errorf("expected '%s', got '%s'", exp, err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected '2', got '%d'", len(entries))
+	}
+
+	for i, exp := range []*CrontabEntry{
+		{Line: 1, Expression: "17 * * * *", User: "root", Command: "cd / && run-parts --report /etc/cron.hourly"},
+		{Line: 2, Expression: "@daily", User: "www-data", Command: "/usr/bin/cleanup"},
+	} {
+		got := entries[i]
+
+		if got.Line != exp.Line || got.Expression != exp.Expression || got.User != exp.User || got.Command != exp.Command {
+			t.Errorf("expected '%+v', got '%+v'", exp, got)
+		}
+	}
+}
+
+func TestCrontab_WithDialect(t *testing.T) {
+	entries, err := ParseCrontab(strings.NewReader("0 0 L * ? /usr/bin/report\n"), WithDialect(DialectNative))
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+
+	got := entries[0].Schedule.Next(testScheduleTime)
+	if exp := time.Date(2023, 1, 31, 0, 0, 0, 0, startupTime.Location()); got.String() != exp.String() {
+		t.Errorf("expected '%s', got '%s'", exp, got)
+	}
+}
+
+func TestCrontab_WithDialect_Fields(t *testing.T) {
+	type testCase struct {
+		data    string
+		opts    []Option
+		expr    string
+		user    string
+		command string
+		err     string
+	}
+
+	for _, tc := range []testCase{
+		{"0 0 12 ? * MON * echo hi\n", []Option{WithDialect(DialectQuartz)}, "0 0 12 ? * MON *", "", "echo hi", ""},
+		{"0 0 12 ? * MON * root echo hi\n", []Option{WithDialect(DialectQuartz), WithUserColumn()}, "0 0 12 ? * MON *", "root", "echo hi", ""},
+		{"0 0 12 ? * MON\n", []Option{WithDialect(DialectQuartz)}, "", "", "", "line 1: missing command in crontab entry given '0 0 12 ? * MON'"},
+		{"0 9 ? * MON echo hi\n", []Option{WithDialect(DialectNative)}, "0 9 ? * MON", "", "echo hi", ""},
+		{"*-*-* 09:00:00 echo hi\n", []Option{WithDialect(DialectSystemd)}, "", "", "", "the dialect 'systemd' is not supported in crontab files"},
+		{"rate(5 minutes) echo hi\n", []Option{WithDialect(DialectEventBridge)}, "", "", "", "the dialect 'eventbridge' is not supported in crontab files"},
+	} {
+		entries, err := ParseCrontab(strings.NewReader(tc.data), tc.opts...)
+		if tc.err != "" || err != nil {
+			if eerr := fmt.Sprintf("%s", err); tc.err != eerr {
+				t.Errorf("'%s': expected '%s', got '%s'", tc.data, tc.err, eerr)
+			}
+
+			continue
+		}
+
+		if got := entries[0]; got.Expression != tc.expr || got.User != tc.user || got.Command != tc.command {
+			t.Errorf("'%s': expected '%s', '%s', '%s', got '%+v'", tc.data, tc.expr, tc.user, tc.command, got)
+		}
+	}
+}
+
+func TestCrontab_LoadCrontab(t *testing.T) {
+	if _, err := LoadCrontab("testdata/missing.crontab"); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
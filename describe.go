@@ -0,0 +1,352 @@
+// Copyright 2022 Alex Schneider. All rights reserved.
+
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// unitNames contains the names of the units of the fields in the descriptions.
+var unitNames = map[fieldType]string{
+	typeMilliseconds: "millisecond",
+	typeSeconds:      "second",
+	typeMinutes:      "minute",
+	typeHours:        "hour",
+	typeDoM:          "day-of-month",
+	typeMonth:        "month",
+	typeDoW:          "day-of-week",
+	typeYear:         "year",
+	typeWeek:         "ISO week",
+	typeDoY:          "day-of-year",
+}
+
+/* ==================================================================================================== */
+
+// Normalize returns the normalized form of the given expression, which is the 7 fields syntax
+// (or 8 fields with milliseconds) of this package with expanded macros and upper case names,
+// e.g. `@daily` is normalized to `0 0 0 * * * *`. The recurrence rules and the rate
+// expressions cannot be normalized.
+func Normalize(expression string, opts ...Option) (string, error) {
+	sp, err := parseSpec(expression, newOptions(opts))
+	if err != nil {
+		return "", err
+	}
+
+	return walkSpec(sp, func(s *schedule) string {
+		return s.fields.String()
+	}, " | ", " & ", " ! ")
+}
+
+// Describe returns a human-readable description of the given expression, e.g.
+// `At 09:00:00, on the 1st Monday of the month` for `0 0 9 ? * 1#1 *`.
+func Describe(expression string, opts ...Option) (string, error) {
+	sp, err := parseSpec(expression, newOptions(opts))
+	if err != nil {
+		return "", err
+	}
+
+	return walkSpec(sp, func(s *schedule) string {
+		return s.fields.describe()
+	}, "; or ", "; and ", "; except ")
+}
+
+// walkSpec joins the strings of all leaf schedules of the given spec by the given operators.
+func walkSpec(sp spec, fn func(*schedule) string, opUnion, opIntersect, opExcept string) (string, error) {
+	join := func(specs []spec, op string) (string, error) {
+		parts := make([]string, len(specs))
+
+		for i, operand := range specs {
+			str, err := walkSpec(operand, fn, opUnion, opIntersect, opExcept)
+			if err != nil {
+				return "", err
+			}
+
+			// A single schedule can be normalized to an intersection, see <cron.fields.String>.
+			if _, ok := operand.(*schedule); !ok || (op != opIntersect && strings.Contains(str, opIntersect)) {
+				str = "(" + str + ")"
+			}
+
+			parts[i] = str
+		}
+
+		return strings.Join(parts, op), nil
+	}
+
+	switch sp := sp.(type) {
+	case *schedule:
+		if sp.spec != nil {
+			return walkSpec(sp.spec, fn, opUnion, opIntersect, opExcept)
+		}
+
+		return fn(sp), nil
+	case union:
+		return join(sp, opUnion)
+	case intersection:
+		return join(sp, opIntersect)
+	case *exception:
+		return join([]spec{sp.spec, sp.excluded}, opExcept)
+	}
+
+	return "", fmt.Errorf("the expression cannot be normalized or described")
+}
+
+/* ==================================================================================================== */
+
+// String returns the normalized form of the fields. The fields, whose DoM and DoW fields have
+// to match both, are normalized to an intersection, because the native syntax combines them.
+func (fs *fields) String() string {
+	if fs.once {
+		return "@reboot"
+	}
+
+	dom, dow := fs.dom.expression, fs.dow.expression
+
+	// Only one of the fields is relevant if both of them have to match and the other one is `*`.
+	if fs.dayMode == dayModeIntersect && dom == "*" && dow != "*" {
+		dom = "?"
+	} else if fs.dayMode == dayModeIntersect && dow == "*" && dom != "*" {
+		dow = "?"
+	} else if fs.dayMode == dayModeIntersect && isRestricted(fs.dom) && isRestricted(fs.dow) {
+		return fs.normalize(dom, "?") + " & " + fs.normalize("?", dow)
+	}
+
+	return fs.normalize(dom, dow)
+}
+
+// normalize returns the normalized form of the fields with the given DoM and DoW fields.
+func (fs *fields) normalize(dom, dow string) string {
+	parts := []string{
+		fs.seconds.expression,
+		fs.minutes.expression,
+		fs.hours.expression,
+		dom,
+		fs.month.expression,
+		dow,
+		fs.year.expression,
+	}
+
+	if fs.millis.expression != "0" {
+		parts = append([]string{fs.millis.expression}, parts...)
+	}
+
+	if fs.week != nil {
+		parts = append(parts, "week="+fs.week.expression)
+	}
+
+	if fs.doy != nil {
+		parts = append(parts, "doy="+fs.doy.expression)
+	}
+
+	return strings.ToUpper(strings.Join(parts, " "))
+}
+
+// describe returns a human-readable description of the fields.
+func (fs *fields) describe() string {
+	if fs.once {
+		return "Once at startup"
+	}
+
+	var clauses []string
+
+	if clock, ok := describeClock(fs); ok {
+		clauses = append(clauses, "At "+clock)
+	} else {
+		var items []string
+
+		for i, f := range []struct {
+			field *field
+			ft    fieldType
+		}{
+			{fs.millis, typeMilliseconds},
+			{fs.seconds, typeSeconds},
+			{fs.minutes, typeMinutes},
+			{fs.hours, typeHours},
+		} {
+			// The coarser units are implied by the finer ones, e.g. `every second`.
+			if (i == 0 && f.field.expression == "0") || (i > 1 && f.field.expression == "*") {
+				continue
+			}
+
+			items = append(items, describeField(f.field.expression, f.ft))
+		}
+
+		clauses = append(clauses, "At "+strings.Join(items, ", "))
+	}
+
+	if days := describeDays(fs); days != "" {
+		clauses = append(clauses, days)
+	}
+
+	for _, f := range []struct {
+		field  *field
+		ft     fieldType
+		prefix string
+	}{
+		{fs.month, typeMonth, "in "},
+		{fs.year, typeYear, "in "},
+		{fs.week, typeWeek, "in "},
+		{fs.doy, typeDoY, "on "},
+	} {
+		if f.field != nil && f.field.expression != "*" {
+			clauses = append(clauses, f.prefix+describeField(f.field.expression, f.ft))
+		}
+	}
+
+	description := strings.Join(clauses, ", ")
+
+	if fs.location != nil {
+		description += " (" + fs.location.String() + ")"
+	}
+
+	return description
+}
+
+// describeClock returns the time like `09:00:00` if the time fields contain single values.
+func describeClock(fs *fields) (string, bool) {
+	var values []int
+
+	for _, f := range []*field{fs.hours, fs.minutes, fs.seconds} {
+		v, err := strconv.Atoi(f.expression)
+		if err != nil {
+			return "", false
+		}
+
+		values = append(values, v)
+	}
+
+	clock := fmt.Sprintf("%02d:%02d:%02d", values[0], values[1], values[2])
+
+	if fs.millis.expression != "0" {
+		ms, err := strconv.Atoi(fs.millis.expression)
+		if err != nil {
+			return "", false
+		}
+
+		clock += fmt.Sprintf(".%03d", ms)
+	}
+
+	return clock, true
+}
+
+func describeDays(fs *fields) string {
+	dom, dow := fs.dom.expression, fs.dow.expression
+
+	domDesc := "on " + describeField(dom, typeDoM)
+	dowDesc := "on " + describeField(dow, typeDoW)
+
+	switch {
+	case dom == "?" && dow == "*", dow == "?" && dom == "*":
+		return ""
+	case dom == "?":
+		return dowDesc
+	case dow == "?":
+		return domDesc
+	case fs.dayMode == dayModeIntersect && dom == "*" && dow == "*":
+		return ""
+	case fs.dayMode == dayModeIntersect && dom == "*":
+		return dowDesc
+	case fs.dayMode == dayModeIntersect && dow == "*":
+		return domDesc
+	case fs.dayMode == dayModeIntersect:
+		return domDesc + " and " + dowDesc
+	case dom == "*" || dow == "*":
+		// The days are calculated from both fields, so that a `*` means every day.
+		return ""
+	}
+
+	return domDesc + " or " + dowDesc
+}
+
+// describeField returns the description of the given field expression.
+func describeField(expression string, ft fieldType) string {
+	var items []string
+
+	for _, expr := range strings.Split(strings.ToUpper(expression), ",") {
+		items = append(items, describeItem(expr, ft))
+	}
+
+	return strings.Join(items, ", ")
+}
+
+func describeItem(expr string, ft fieldType) string {
+	unit := unitNames[ft]
+
+	if matches := reLastDoWInMonth.FindStringSubmatch(expr); len(matches) == 2 && ft == typeDoW {
+		return "the last " + describeValue(matches[1], ft) + " of the month"
+	} else if matches := reDoWInSpecificWeek.FindStringSubmatch(expr); len(matches) == 3 {
+		nth, _ := strconv.Atoi(matches[2])
+
+		return "the " + ordinal(nth) + " " + describeValue(matches[1], ft) + " of the month"
+	} else if matches := reWeekdayDoM.FindStringSubmatch(expr); len(matches) == 2 {
+		return "the weekday nearest day " + describeValue(matches[1], ft) + " of the month"
+	}
+
+	value, step, hasStep := strings.Cut(expr, "/")
+	v1, v2, isRange := strings.Cut(value, "-")
+
+	switch {
+	case expr == "*":
+		return "every " + unit
+	case expr == "R":
+		return "a random " + unit
+	case expr == ".":
+		return "the current " + unit
+	case expr == "L" && ft == typeDoM:
+		return "the last day of the month"
+	case expr == "L":
+		return time.Saturday.String()
+	case expr == "LW":
+		return "the last weekday of the month"
+	case hasStep && step == "R":
+		return "a random " + unit + " between " + describeValue(v1, ft) + " and " + describeValue(v2, ft)
+	case hasStep && value == "*":
+		return "every " + step + " " + unit + "s"
+	case hasStep && isRange:
+		return "every " + step + " " + unit + "s from " + describeValue(v1, ft) + " through " + describeValue(v2, ft)
+	case hasStep:
+		return "every " + step + " " + unit + "s starting at " + describeValue(value, ft)
+	case isRange && (ft == typeDoW || ft == typeMonth):
+		return describeValue(v1, ft) + " through " + describeValue(v2, ft)
+	case isRange:
+		return unit + "s " + v1 + " through " + v2
+	case ft == typeDoW || ft == typeMonth:
+		return describeValue(value, ft)
+	}
+
+	return unit + " " + value
+}
+
+// describeValue returns the names of the months and the days of the week.
+func describeValue(value string, ft fieldType) string {
+	_, _, _, n, err := toNumVal(value)
+	if err != nil {
+		return value
+	}
+
+	switch ft {
+	case typeDoW:
+		return time.Weekday(n % 7).String()
+	case typeMonth:
+		return time.Month(n).String()
+	}
+
+	return value
+}
+
+func ordinal(n int) string {
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+		return strconv.Itoa(n) + "th"
+	case n%10 == 1:
+		return strconv.Itoa(n) + "st"
+	case n%10 == 2:
+		return strconv.Itoa(n) + "nd"
+	case n%10 == 3:
+		return strconv.Itoa(n) + "rd"
+	}
+
+	return strconv.Itoa(n) + "th"
+}
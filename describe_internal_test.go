@@ -0,0 +1,114 @@
+package cron
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDescribe_Normalize(t *testing.T) {
+	type testCase struct {
+		expr string
+		opts []Option
+		exp  string
+	}
+
+	tests := []testCase{
+		{"@daily", nil, "0 0 0 * * * *"},
+		{"@reboot", nil, "@reboot"},
+		{"*/5 * * * *", nil, "0 */5 * * * * *"},
+		{"0 0 9 ? * mon-fri", nil, "0 0 9 ? * MON-FRI *"},
+		{"250 0 0 12 * * ? *", nil, "250 0 0 12 * * ? *"},
+		{"0 0 0 ? * 5#1 * week=1", nil, "0 0 0 ? * 5#1 * WEEK=1"},
+		{"0 0 * * mon", []Option{WithDialect(DialectVixie)}, "0 0 0 ? * MON *"},
+		{"(0 0 9 ? * MON | 0 0 10 ? * SAT) ! 0 0 * 1 * ? *", nil, "(0 0 9 ? * MON * | 0 0 10 ? * SAT *) ! 0 0 * 1 * ? *"},
+		{"0 0 */2 * MON", []Option{WithDialect(DialectVixie)}, "0 0 0 */2 * ? * & 0 0 0 ? * MON *"},
+		{"Mon *-*-01 00:00:00", []Option{WithDialect(DialectSystemd)}, "0 0 0 1 * ? * & 0 0 0 ? * MON *"},
+	}
+
+	for _, tc := range tests {
+		got, err := Normalize(tc.expr, tc.opts...)
+		if err != nil {
+			t.Errorf("'%s': unexpected error '%s'", tc.expr, err)
+		} else if got != tc.exp {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.exp, got)
+		}
+	}
+
+	if _, err := Normalize("0 0 25 * * ? *"); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+
+	expErr := "the expression cannot be normalized or described"
+	if _, err := Normalize("FREQ=DAILY", WithDialect(DialectRRule)); err == nil || err.Error() != expErr {
+		t.Errorf("expected '%s', got '%v'", expErr, err)
+	}
+}
+
+func TestDescribe_Normalize_Equal(t *testing.T) {
+	ref := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+
+	for _, tc := range []struct {
+		expr string
+		opts []Option
+	}{
+		{"0 0 */2 * MON", []Option{WithDialect(DialectVixie)}},
+		{"Mon *-*-01 00:00:00", []Option{WithDialect(DialectSystemd)}},
+		{"0 0 9 ? * MON | 0 0 9 1 * ? *", nil},
+	} {
+		s1, err := Parse(tc.expr, tc.opts...)
+		if err != nil {
+			t.Fatalf("'%s': unexpected error '%s'", tc.expr, err)
+		}
+
+		normalized, err := Normalize(tc.expr, tc.opts...)
+		if err != nil {
+			t.Fatalf("'%s': unexpected error '%s'", tc.expr, err)
+		}
+
+		s2, err := Parse(normalized)
+		if err != nil {
+			t.Fatalf("'%s': unexpected error '%s'", normalized, err)
+		}
+
+		if !s1.Equal(s2) {
+			t.Errorf("'%s': expected equal to '%s'", tc.expr, normalized)
+		}
+
+		if next1, next2 := s1.Next(ref), s2.Next(ref); !next1.Equal(next2) {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, next1, next2)
+		}
+	}
+}
+
+func TestDescribe_Describe(t *testing.T) {
+	type testCase struct {
+		expr string
+		exp  string
+	}
+
+	tests := []testCase{
+		{"@reboot", "Once at startup"},
+		{"@daily", "At 00:00:00"},
+		{"* * * * * * *", "At every second"},
+		{"*/5 * * * *", "At second 0, every 5 minutes"},
+		{"250 0 0 12 * * ? *", "At 12:00:00.250"},
+		{"0 0 9 ? * 1#1 *", "At 09:00:00, on the 1st Monday of the month"},
+		{"0 */15 9-17 ? * MON-FRI *", "At second 0, every 15 minutes, hours 9 through 17, on Monday through Friday"},
+		{"0 0 0 L * ? *", "At 00:00:00, on the last day of the month"},
+		{"0 0 0 15W * ? *", "At 00:00:00, on the weekday nearest day 15 of the month"},
+		{"0 0 0 1 * MON *", "At 00:00:00, on day-of-month 1 or on Monday"},
+		{"0 0 12 15 JAN-MAR ? 2025", "At 12:00:00, on day-of-month 15, in January through March, in year 2025"},
+		{"0 0 0 ? * 5#1 * week=1", "At 00:00:00, on the 1st Friday of the month, in ISO week 1"},
+		{"0 0 2-6/R * * ? *", "At second 0, minute 0, a random hour between 2 and 6"},
+		{"0 0 9 ? * MON | 0 0 10 ? * SAT", "At 09:00:00, on Monday; or At 10:00:00, on Saturday"},
+	}
+
+	for _, tc := range tests {
+		got, err := Describe(tc.expr)
+		if err != nil {
+			t.Errorf("'%s': unexpected error '%s'", tc.expr, err)
+		} else if got != tc.exp {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.exp, got)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2022 Alex Schneider. All rights reserved.
+
+package cron
+
+import "time"
+
+const (
+	// DialectNative is the extended syntax of this package.
+	DialectNative Dialect = iota
+	// DialectQuartz is the syntax of the Quartz scheduler, where the seconds field is
+	// required, the DoW field uses the values `1-7` with `SUN=1` and the special
+	// character `?` is required in either the DoM or the DoW field.
+	DialectQuartz
+	// DialectVixie is the strict classic 5 fields syntax of the Vixie cron (POSIX crontab),
+	// which rejects all non-standard special characters. The DoM and DoW fields are
+	// combined as in the Vixie cron: if one of them starts with `*`, both fields
+	// must match, otherwise any of them.
+	DialectVixie
+	// DialectSystemd is the syntax of the calendar events of the systemd timers
+	// (`OnCalendar=`), like `Mon..Fri *-*-* 09:00:00` or `*-*-01 04:00:00 Europe/Berlin`.
+	// The DoW and the date must both match.
+	DialectSystemd
+	// DialectEventBridge is the syntax of the schedules of the AWS EventBridge, which are
+	// either `cron()` expressions like `cron(0 12 * * ? *)` with 6 fields from the minutes
+	// to the year in the Quartz syntax, or `rate()` expressions like `rate(5 minutes)`.
+	DialectEventBridge
+	// DialectRRule is the syntax of the recurrence rules of the RFC 5545 (iCalendar) like
+	// `FREQ=MONTHLY;BYDAY=-1FR;BYHOUR=17`, optionally accompanied by the `DTSTART` and
+	// `EXDATE` properties, e.g. `DTSTART:20230101T090000Z RRULE:FREQ=DAILY;COUNT=10`.
+	DialectRRule
+)
+
+// Dialect defines the syntax of a cron expression.
+type Dialect int
+
+// String implements the <fmt.Stringer> interface.
+func (d Dialect) String() string {
+	switch d {
+	case DialectNative:
+		return "native"
+	case DialectQuartz:
+		return "quartz"
+	case DialectVixie:
+		return "vixie"
+	case DialectSystemd:
+		return "systemd"
+	case DialectEventBridge:
+		return "eventbridge"
+	case DialectRRule:
+		return "rrule"
+	}
+
+	return "unknown"
+}
+
+/* ==================================================================================================== */
+
+// toNativeExpression converts the given expression of the given dialect
+// to the syntax of this package. The returned location is not nil only if
+// the expression of the dialect contains a timezone.
+func toNativeExpression(expression string, dialect Dialect) (string, *time.Location, error) {
+	var err error
+
+	switch dialect {
+	case DialectQuartz:
+		expression, err = FromQuartz(expression)
+	case DialectVixie:
+		expression, err = fromVixie(expression)
+	case DialectSystemd:
+		return fromSystemd(expression)
+	case DialectEventBridge:
+		expression, err = fromEventBridge(expression)
+	}
+
+	return expression, nil, err
+}
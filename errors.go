@@ -0,0 +1,90 @@
+// Copyright 2022 Alex Schneider. All rights reserved.
+
+package cron
+
+import "strings"
+
+// ParseError is returned if an expression cannot be parsed.
+type ParseError struct {
+	// Expression contains the invalid expression.
+	Expression string
+
+	// Position contains the byte offset of the invalid part of the
+	// expression or -1 if the invalid part is not known.
+	Position int
+
+	// Err contains the underlying error.
+	Err error
+}
+
+// fieldError reports the index of an invalid field of the expression.
+type fieldError struct {
+	index     int
+	extension bool
+	err       error
+}
+
+/* ==================================================================================================== */
+
+// Error implements the <error> interface.
+func (e *ParseError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Error implements the <error> interface.
+func (e *fieldError) Error() string {
+	return e.err.Error()
+}
+
+/* ==================================================================================================== */
+
+// newFieldsParseError returns a new <cron.ParseError> for the given original expression. The
+// position of an invalid field is only known if the expression was not converted from a macro
+// or another dialect.
+func newFieldsParseError(original, expression string, fieldsCount int, err error) *ParseError {
+	fe, ok := err.(*fieldError)
+	if !ok {
+		return &ParseError{Expression: original, Position: -1, Err: err}
+	}
+
+	position := -1
+	if original == expression {
+		position = fieldPosition(expression, fe.index, fe.extension, fieldsCount)
+	}
+
+	return &ParseError{Expression: original, Position: position, Err: fe.err}
+}
+
+// fieldPosition returns the byte offset of the field with the given index in the given
+// expression or -1 if the field is implicit. The fields are counted from the seconds on,
+// or from the milliseconds on in the 8 fields form. The extension fields are counted
+// separately.
+func fieldPosition(expression string, index int, extension bool, fieldsCount int) int {
+	var tokens, extensionTokens [][]int
+
+	for _, idx := range reFieldsMatcher.FindAllStringIndex(expression, -1) {
+		if strings.Contains(expression[idx[0]:idx[1]], "=") {
+			extensionTokens = append(extensionTokens, idx)
+		} else {
+			tokens = append(tokens, idx)
+		}
+	}
+
+	// The seconds field is implicit in the 5 fields form.
+	if extension {
+		tokens = extensionTokens
+	} else if fieldsCount == 5 {
+		index--
+	}
+
+	if index < 0 || index >= len(tokens) {
+		return -1
+	}
+
+	return tokens[index][0]
+}
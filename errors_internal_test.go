@@ -0,0 +1,49 @@
+package cron
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestErrors_ParseError(t *testing.T) {
+	type testCase struct {
+		expr     string
+		opts     []Option
+		position int
+	}
+
+	tests := []testCase{
+		{"0 0 25 * * ? *", nil, 4},
+		{"0 0 0 32 * ? *", nil, 6},
+		{"1000 0 0 0 * * ? *", nil, 0},
+		{"0 61 * * *", nil, 2},
+		{"0 0 0 * * ? * doy=400", nil, 14},
+		{"0 0 * * * | 0 61 * * *", nil, 14},
+		{"0 0 0 * * ? * (", nil, 14},
+		{"@foo", nil, -1},
+		{"61 * * * *", []Option{WithDialect(DialectVixie)}, -1},
+	}
+
+	for _, tc := range tests {
+		_, err := Parse(tc.expr, tc.opts...)
+
+		var pe *ParseError
+		if !errors.As(err, &pe) {
+			t.Errorf("'%s': expected '%T', got '%#v'", tc.expr, pe, err)
+
+			continue
+		}
+
+		if pe.Expression != tc.expr {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.expr, pe.Expression)
+		}
+
+		if pe.Position != tc.position {
+			t.Errorf("'%s': expected '%d', got '%d'", tc.expr, tc.position, pe.Position)
+		}
+
+		if pe.Unwrap() == nil || pe.Error() != pe.Unwrap().Error() {
+			t.Errorf("'%s': unexpected underlying error '%v'", tc.expr, pe.Unwrap())
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2022 Alex Schneider. All rights reserved.
+
+package cron
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var reEventBridgeRate = regexp.MustCompile(`^rate\(\s*(\d+)\s+(minutes?|hours?|days?)\s*\)$`)
+
+// eventBridgeUnits contains the units of the EventBridge rate expressions.
+var eventBridgeUnits = map[string]time.Duration{
+	"minute": time.Minute,
+	"hour":   time.Hour,
+	"day":    24 * time.Hour,
+}
+
+// interval is a spec which is executed periodically from the anchor time on.
+type interval struct {
+	anchor    time.Time
+	hasAnchor bool // Whether the anchor is given explicitly by <cron.WithReferenceTime>.
+	period    time.Duration
+}
+
+/* ==================================================================================================== */
+
+// fromEventBridge converts the given EventBridge expression like `cron(0 12 * * ? *)`
+// to the 7 fields syntax of this package. The EventBridge expression has 6 fields
+// from the minutes to the year and uses the Quartz syntax otherwise.
+func fromEventBridge(expression string) (string, error) {
+	if strings.HasPrefix(expression, "rate(") {
+		return "", fmt.Errorf("the rate expressions cannot be converted to cron expressions")
+	} else if !strings.HasPrefix(expression, "cron(") || !strings.HasSuffix(expression, ")") {
+		return "", fmt.Errorf("invalid EventBridge expression given '%s'", expression)
+	}
+
+	fieldsParts := reFieldsMatcher.FindAllString(expression[len("cron("):len(expression)-1], -1)
+	if len(fieldsParts) != 6 {
+		return "", fmt.Errorf("invalid EventBridge expression given '%s'", expression)
+	}
+
+	return FromQuartz("0 " + strings.Join(fieldsParts, " "))
+}
+
+// parseEventBridgeSpec parses the given EventBridge expression, which is either
+// a `cron()` or a `rate()` expression. The rate expressions are anchored at the
+// reference time, see <cron.WithReferenceTime>.
+func parseEventBridgeSpec(expression string, o *options) (spec, error) {
+	expression = strings.TrimSpace(expression)
+
+	if !strings.HasPrefix(expression, "rate(") {
+		return newLeafSpec(expression, o)
+	}
+
+	matches := reEventBridgeRate.FindStringSubmatch(expression)
+	if len(matches) != 3 {
+		return nil, fmt.Errorf("invalid EventBridge expression given '%s'", expression)
+	}
+
+	value, err := strconv.Atoi(matches[1])
+	if err != nil || value < 1 {
+		return nil, fmt.Errorf("invalid value in rate expression given '%s'", expression)
+	}
+
+	// EventBridge requires the singular unit for the value 1 and the plural otherwise.
+	unit := strings.TrimSuffix(matches[2], "s")
+	if (value == 1) != (unit == matches[2]) {
+		return nil, fmt.Errorf("invalid unit in rate expression given '%s'", expression)
+	}
+
+	// The period must be representable as a <time.Duration> (about 292 years).
+	if int64(value) > math.MaxInt64/int64(eventBridgeUnits[unit]) {
+		return nil, fmt.Errorf("invalid value in rate expression given '%s'", expression)
+	}
+
+	return &interval{
+		anchor:    o.now.Truncate(resolution),
+		hasAnchor: o.hasNow,
+		period:    time.Duration(value) * eventBridgeUnits[unit],
+	}, nil
+}
+
+/* ==================================================================================================== */
+
+func (iv *interval) next(referenceTime time.Time) (time.Time, State) {
+	if referenceTime.IsZero() {
+		return time.Time{}, StateZeroTime
+	} else if referenceTime.Before(iv.anchor) {
+		return iv.anchor.In(referenceTime.Location()), StateFound
+	}
+
+	// The elapsed periods are added separately from the next one, which cannot overflow.
+	n := referenceTime.Sub(iv.anchor) / iv.period
+
+	return iv.anchor.Add(n * iv.period).Add(iv.period).In(referenceTime.Location()), StateFound
+}
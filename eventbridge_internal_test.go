@@ -0,0 +1,152 @@
+package cron
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestEventBridge_fromEventBridge(t *testing.T) {
+	type testCase struct {
+		expr string
+		exp  string
+		err  string
+	}
+
+	for _, tc := range []testCase{
+		{"cron(0 12 * * ? *)", "0 0 12 * * ? *", ``},
+		{"cron(15 10 ? * 6L 2022-2025)", "0 15 10 ? * 5L 2022-2025", ``},
+		{"cron(0/5 8-17 ? * MON-FRI *)", "0 0/5 8-17 ? * MON-FRI *", ``},
+		{"cron(0 12 * * * *)", "", `the special character '?' is required in either the DoM or the DoW field of Quartz expressions`},
+		{"cron(0 12 * * ?)", "", `invalid EventBridge expression given 'cron(0 12 * * ?)'`},
+		{"cron(0 12 * * ? *", "", `invalid EventBridge expression given 'cron(0 12 * * ? *'`},
+		{"0 12 * * ? *", "", `invalid EventBridge expression given '0 12 * * ? *'`},
+		{"rate(5 minutes)", "", `the rate expressions cannot be converted to cron expressions`},
+	} {
+		got, err := fromEventBridge(tc.expr)
+		if got != tc.exp {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.exp, got)
+		}
+
+		if tc.err != "" || err != nil {
+			if eerr := fmt.Sprintf("%s", err); tc.err != eerr {
+				t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.err, eerr)
+			}
+		}
+	}
+}
+
+func TestEventBridge_parseEventBridgeSpec(t *testing.T) {
+	type testCase struct {
+		expr   string
+		period time.Duration
+		err    string
+	}
+
+	for _, tc := range []testCase{
+		{"rate(1 minute)", time.Minute, ``},
+		{"rate(5 minutes)", 5 * time.Minute, ``},
+		{" rate(12 hours) ", 12 * time.Hour, ``},
+		{"rate(7 days)", 7 * 24 * time.Hour, ``},
+		{"rate(0 minutes)", 0, `invalid value in rate expression given 'rate(0 minutes)'`},
+		{"rate(106751 days)", 106751 * 24 * time.Hour, ``},
+		{"rate(106752 days)", 0, `invalid value in rate expression given 'rate(106752 days)'`},
+		{"rate(200000 days)", 0, `invalid value in rate expression given 'rate(200000 days)'`},
+		{"rate(281474976710656 days)", 0, `invalid value in rate expression given 'rate(281474976710656 days)'`},
+		{"rate(99999999999999999999 minutes)", 0, `invalid value in rate expression given 'rate(99999999999999999999 minutes)'`},
+		{"rate(1 minutes)", 0, `invalid unit in rate expression given 'rate(1 minutes)'`},
+		{"rate(2 hour)", 0, `invalid unit in rate expression given 'rate(2 hour)'`},
+		{"rate(2 weeks)", 0, `invalid EventBridge expression given 'rate(2 weeks)'`},
+		{"rate(minutes)", 0, `invalid EventBridge expression given 'rate(minutes)'`},
+	} {
+		sp, err := parseEventBridgeSpec(tc.expr, newOptions([]Option{WithDialect(DialectEventBridge)}))
+
+		if tc.err != "" || err != nil {
+			if eerr := fmt.Sprintf("%s", err); tc.err != eerr {
+				t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.err, eerr)
+			}
+
+			continue
+		}
+
+		iv, ok := sp.(*interval)
+		if !ok {
+			t.Fatalf("'%s': expected '*cron.interval', got '%T'", tc.expr, sp)
+		}
+
+		if iv.period != tc.period {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.period, iv.period)
+		}
+	}
+}
+
+func TestEventBridge_interval_next(t *testing.T) {
+	type testCase struct {
+		ref   time.Time
+		exp   time.Time
+		state State
+	}
+
+	anchor := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+	iv := &interval{anchor: anchor, period: 5 * time.Minute}
+
+	for _, tc := range []testCase{
+		{time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC), anchor, StateFound},
+		{anchor, time.Date(2023, 1, 1, 12, 5, 0, 0, time.UTC), StateFound},
+		{time.Date(2023, 1, 1, 12, 7, 30, 0, time.UTC), time.Date(2023, 1, 1, 12, 10, 0, 0, time.UTC), StateFound},
+		{time.Date(2023, 1, 1, 12, 10, 0, 0, time.UTC), time.Date(2023, 1, 1, 12, 15, 0, 0, time.UTC), StateFound},
+		{time.Time{}, time.Time{}, StateZeroTime},
+	} {
+		got, state := iv.next(tc.ref)
+		if got.String() != tc.exp.String() {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.ref, tc.exp, got)
+		}
+
+		if state != tc.state {
+			t.Errorf("'%s': expected '%d', got '%d'", tc.ref, tc.state, state)
+		}
+	}
+
+	// The next execution of the longest periods doesn't overflow.
+	iv = &interval{anchor: anchor, period: 106751 * 24 * time.Hour}
+
+	ref, exp := anchor.Add(iv.period).Add(time.Hour), anchor.Add(iv.period).Add(iv.period)
+	if got, _ := iv.next(ref); !got.Equal(exp) {
+		t.Errorf("'%s': expected '%s', got '%s'", ref, exp, got)
+	}
+}
+
+func TestEventBridge_WithDialect(t *testing.T) {
+	s, err := Parse("cron(0 12 ? * MON-FRI *)", WithDialect(DialectEventBridge))
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+
+	got := s.Next(testScheduleTime)
+	if exp := time.Date(2023, 1, 2, 12, 0, 0, 0, startupTime.Location()); got.String() != exp.String() {
+		t.Errorf("expected '%s', got '%s'", exp, got)
+	}
+
+	s, err = Parse("rate(5 minutes)", WithDialect(DialectEventBridge))
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+
+	now := time.Now()
+	if got := s.Next(now); got.Sub(now) <= 0 || got.Sub(now) > 5*time.Minute {
+		t.Errorf("expected the next execution within 5 minutes, got '%s'", got)
+	}
+
+	// The rate expressions are anchored at the reference time.
+	ref := time.Date(2023, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	s, err = Parse("rate(5 minutes)", WithDialect(DialectEventBridge), WithReferenceTime(ref))
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+
+	got = s.Next(time.Date(2023, 1, 1, 12, 7, 0, 0, time.UTC))
+	if exp := time.Date(2023, 1, 1, 12, 10, 0, 0, time.UTC); got.String() != exp.String() {
+		t.Errorf("expected '%s', got '%s'", exp, got)
+	}
+}
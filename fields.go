@@ -8,6 +8,7 @@ import (
 	"regexp"
 	"sort"
 	"strings"
+	"time"
 )
 
 type combination struct {
@@ -18,40 +19,75 @@ type combination struct {
 type field struct {
 	expression   string
 	combinations []*combination
+	bits         bitset // Plain values of the combinations, the year field has no bitset.
 }
 
+const (
+	// dayModeUnion calculates the days from the best possible value of the DoM and DoW fields.
+	dayModeUnion dayMode = iota
+	// dayModeIntersect calculates the days matching both, the DoM and DoW fields.
+	dayModeIntersect
+)
+
+type dayMode int
+
 type fields struct {
-	seconds *field
-	minutes *field
-	hours   *field
-	dom     *field
-	month   *field
-	dow     *field
-	year    *field
-	once    bool
+	millis   *field
+	seconds  *field
+	minutes  *field
+	hours    *field
+	dom      *field
+	month    *field
+	dow      *field
+	year     *field
+	week     *field // Optional ISO week-of-year extension field.
+	doy      *field // Optional day-of-year extension field.
+	dayMode  dayMode
+	location *time.Location // Optional timezone of the expression.
+	zoned    bool           // Whether the timezone is given by the expression itself.
+	source   string         // Original expression, empty if converted from a macro or a dialect.
+	maxYear  int
+	once     bool
 }
 
 var reFieldsMatcher = regexp.MustCompile(`\S+`)
 
+// extensionFields contains the names of the optional extension fields,
+// which can be appended to the expression as `name=value`.
+var extensionFields = map[string]fieldType{
+	"WEEK": typeWeek,
+	"DOY":  typeDoY,
+}
+
 /* ==================================================================================================== */
 
-func getFields(expression string) (*fields, error) {
+func getFields(expression string, o *options) (*fields, error) {
 	expression = strings.TrimSpace(expression)
+	original := expression
+
+	if o.minYear > o.maxYear {
+		return nil, fmt.Errorf("invalid year range given '%d-%d'", o.minYear, o.maxYear)
+	}
+
+	expression, location, err := toNativeExpression(expression, o.dialect)
+	if err != nil {
+		return nil, newFieldsParseError(original, expression, 0, err)
+	}
 
 	e, err := expressionFromMacro(expression)
 	if err != nil {
-		return nil, err
+		return nil, newFieldsParseError(original, expression, 0, err)
 	} else if e == "~" {
 		return &fields{once: true}, nil
 	} else if e != "" {
 		expression = e
 	}
 
-	fieldsParts := reFieldsMatcher.FindAllString(expression, -1)
+	fieldsParts, extensionParts := splitExtensionFields(reFieldsMatcher.FindAllString(expression, -1))
 	fieldsCount := len(fieldsParts)
 
-	if fieldsCount < 5 || fieldsCount > 7 {
-		return nil, fmt.Errorf("invalid expression given '%s'", expression)
+	if fieldsCount < 5 || fieldsCount > 8 {
+		return nil, newFieldsParseError(original, expression, fieldsCount, fmt.Errorf("invalid expression given '%s'", expression))
 	} else if fieldsCount < 7 {
 		if fieldsCount == 5 {
 			fieldsParts = append([]string{"0"}, fieldsParts...)
@@ -60,53 +96,85 @@ func getFields(expression string) (*fields, error) {
 		fieldsParts = append(fieldsParts, "*")
 	}
 
-	return createFields(fieldsParts)
-}
+	fields, err := createFields(fieldsParts, o)
+	if err != nil {
+		return nil, newFieldsParseError(original, expression, fieldsCount, err)
+	}
 
-func createFields(fieldsParts []string) (*fields, error) {
-	fields := &fields{}
+	if err := fields.createExtensionFields(extensionParts, o); err != nil {
+		return nil, newFieldsParseError(original, expression, fieldsCount, err)
+	}
 
-	field, err := createField(fieldsParts[0], typeSeconds)
-	if err != nil {
-		return nil, err
+	// The Vixie cron combines both fields only if none of them starts with `*`.
+	if o.dialect == DialectVixie && (strings.HasPrefix(fieldsParts[3], "*") || strings.HasPrefix(fieldsParts[5], "*")) {
+		fields.dayMode = dayModeIntersect
+	} else if o.dialect == DialectSystemd {
+		fields.dayMode = dayModeIntersect
 	}
-	fields.seconds = field
 
-	field, err = createField(fieldsParts[1], typeMinutes)
-	if err != nil {
-		return nil, err
+	fields.location, fields.zoned = location, location != nil
+	if location == nil {
+		fields.location = o.location
 	}
-	fields.minutes = field
 
-	field, err = createField(fieldsParts[2], typeHours)
-	if err != nil {
-		return nil, err
+	if original == expression {
+		fields.source = original
 	}
-	fields.hours = field
 
-	field, err = createField(fieldsParts[3], typeDoM)
-	if err != nil {
-		return nil, err
+	if !fields.isSatisfiable(o) {
+		return nil, newFieldsParseError(original, expression, fieldsCount, fmt.Errorf("the cronjob will never run; the days never occur in the given months and years"))
 	}
-	fields.dom = field
 
-	field, err = createField(fieldsParts[4], typeMonth)
-	if err != nil {
-		return nil, err
+	return fields, nil
+}
+
+func splitExtensionFields(parts []string) ([]string, []string) {
+	var fieldsParts, extensionParts []string
+
+	for _, part := range parts {
+		if strings.Contains(part, "=") {
+			extensionParts = append(extensionParts, part)
+		} else {
+			fieldsParts = append(fieldsParts, part)
+		}
 	}
-	fields.month = field
 
-	field, err = createField(fieldsParts[5], typeDoW)
-	if err != nil {
-		return nil, err
+	return fieldsParts, extensionParts
+}
+
+func createFields(fieldsParts []string, o *options) (*fields, error) {
+	fields := &fields{maxYear: o.maxYear}
+
+	// The milliseconds field is only present in the 8 fields form.
+	offset := 0
+	if len(fieldsParts) == 8 {
+		offset = 1
+	} else {
+		fieldsParts = append([]string{"0"}, fieldsParts...)
 	}
-	fields.dow = field
 
-	field, err = createField(fieldsParts[6], typeYear)
-	if err != nil {
-		return nil, err
+	targets := []struct {
+		field **field
+		ft    fieldType
+	}{
+		{&fields.millis, typeMilliseconds},
+		{&fields.seconds, typeSeconds},
+		{&fields.minutes, typeMinutes},
+		{&fields.hours, typeHours},
+		{&fields.dom, typeDoM},
+		{&fields.month, typeMonth},
+		{&fields.dow, typeDoW},
+		{&fields.year, typeYear},
+	}
+
+	for i, target := range targets {
+		field, err := createField(fieldsParts[i], target.ft, o)
+		if err != nil {
+			return nil, &fieldError{index: i - 1 + offset, err: err}
+		}
+
+		*target.field = field
 	}
-	fields.year = field
 
 	if fields.dom.combinations[0].unit == "?" && fields.dow.combinations[0].unit == "?" {
 		return nil, fmt.Errorf("the cronjob will never run; both DoM and DoW contain the special character '?'")
@@ -115,7 +183,48 @@ func createFields(fieldsParts []string) (*fields, error) {
 	return fields, nil
 }
 
-func createField(expression string, ft fieldType) (*field, error) {
+func (fs *fields) createExtensionFields(extensionParts []string, o *options) error {
+	for i, part := range extensionParts {
+		if err := fs.createExtensionField(part, o); err != nil {
+			return &fieldError{index: i, extension: true, err: err}
+		}
+	}
+
+	return nil
+}
+
+func (fs *fields) createExtensionField(part string, o *options) error {
+	name, expression, _ := strings.Cut(part, "=")
+
+	ft, ok := extensionFields[strings.ToUpper(name)]
+	if !ok {
+		return fmt.Errorf("unsupported extension field given '%s'", part)
+	}
+
+	field, err := createField(expression, ft, o)
+	if err != nil {
+		return err
+	}
+
+	switch ft {
+	case typeWeek:
+		if fs.week != nil {
+			return fmt.Errorf("duplicate extension field given '%s'", part)
+		}
+
+		fs.week = field
+	case typeDoY:
+		if fs.doy != nil {
+			return fmt.Errorf("duplicate extension field given '%s'", part)
+		}
+
+		fs.doy = field
+	}
+
+	return nil
+}
+
+func createField(expression string, ft fieldType, o *options) (*field, error) {
 	field := &field{
 		expression: expression,
 	}
@@ -127,15 +236,26 @@ func createField(expression string, ft fieldType) (*field, error) {
 
 		expr = strings.ToUpper(expr)
 
+		if ft == typeYear {
+			values, ok, err := getOpenYearValues(expr, o)
+			if err != nil {
+				return nil, err
+			} else if ok {
+				field.combinations = append(field.combinations, &combination{values: values, unit: "/"})
+
+				continue
+			}
+		}
+
 		if isFlexValue(expr) {
-			values, unit, err := getFlexValues(expr, ft)
+			values, unit, err := getFlexValues(expr, ft, o)
 			if err != nil {
 				return nil, err
 			}
 
 			field.combinations = append(field.combinations, &combination{values: values, unit: unit})
 		} else {
-			values, err := getFixValues(expr, ft)
+			values, err := getFixValues(expr, ft, o)
 			if err != nil {
 				return nil, err
 			}
@@ -146,9 +266,101 @@ func createField(expression string, ft fieldType) (*field, error) {
 
 	field.mergeCombinations()
 
+	if ft != typeYear {
+		_, max := getMinMax(ft, o)
+
+		field.bits = newBitset(nil, max)
+
+		for _, combi := range field.combinations {
+			if combi.unit == "" {
+				field.bits = newBitset(combi.values, max)
+			}
+		}
+	}
+
 	return field, nil
 }
 
+// nextYear returns the first year of the year field,
+// that is greater than or equal to the given year.
+func (fs *fields) nextYear(year int) (int, bool) {
+	next := -1
+
+	for _, combi := range fs.year.combinations {
+		var v int
+
+		if combi.unit == "/" {
+			start, step := combi.values[0], combi.values[1]
+
+			v = start
+			if year > start {
+				v = start + (year-start+step-1)/step*step
+			}
+
+			if v > fs.maxYear {
+				continue
+			}
+		} else {
+			i := sort.SearchInts(combi.values, year)
+			if i == len(combi.values) {
+				continue
+			}
+
+			v = combi.values[i]
+		}
+
+		if next < 0 || v < next {
+			next = v
+		}
+	}
+
+	return next, next >= 0
+}
+
+// isSatisfiable reports whether at least one day matches the day fields, the month field, the
+// year field and the extension fields, e.g. `31 2` never matches. The years are checked from
+// the lower bound of the year range on, so that the result does not depend on the current
+// date, up to the length of the Gregorian cycle of 400 years, after which the days of the
+// week repeat. The excluded days of the calendar are not considered.
+func (fs *fields) isSatisfiable(o *options) bool {
+	matching, _ := fs.matchingYears(o.minYear, true)
+
+	return matching > 0
+}
+
+// matchingYears returns the number of the years of the year field from the given year on, in
+// which at least one day matches, and the number of all years of the year field within the
+// checked years, see <cron.fields.isSatisfiable>. The counting stops at the first matching
+// year if requested.
+func (fs *fields) matchingYears(year int, stop bool) (matching, total int) {
+	s := &schedule{fields: fs}
+
+	for i := 0; i < 400; i++ {
+		y, ok := fs.nextYear(year)
+		if !ok {
+			break
+		}
+
+		total++
+
+		for m, ok := fs.month.bits.next(1); ok; m, ok = fs.month.bits.next(m + 1) {
+			if s.getDays(time.Date(y, time.Month(m), 1, 0, 0, 0, 0, time.UTC)) != 0 {
+				matching++
+
+				break
+			}
+		}
+
+		if stop && matching > 0 {
+			break
+		}
+
+		year = y + 1
+	}
+
+	return matching, total
+}
+
 func (f *field) mergeCombinations() {
 	if len(f.combinations) < 2 {
 		return
@@ -4,11 +4,12 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 )
 
 func TestFields_getFields(t *testing.T) {
 	expression := "@test"
-	f, err := getFields(expression)
+	f, err := getFields(expression, newOptions(nil))
 	if eerr := fmt.Sprintf("%s", err); eerr != `unsupported macro given '@test'` {
 		t.Errorf("'%s': expected '%s', got '%s'", expression, `unsupported macro given '@test'`, eerr)
 	}
@@ -17,7 +18,7 @@ func TestFields_getFields(t *testing.T) {
 	}
 
 	expression = "@reboot"
-	f, err = getFields(expression)
+	f, err = getFields(expression, newOptions(nil))
 	if err != nil {
 		t.Errorf("'%s': unexpected error: '%#v'", expression, err)
 	}
@@ -29,7 +30,7 @@ func TestFields_getFields(t *testing.T) {
 	}
 
 	expression = "@yearly"
-	f, err = getFields(expression)
+	f, err = getFields(expression, newOptions(nil))
 	if err != nil {
 		t.Errorf("'%s': unexpected error: '%#v'", expression, err)
 	}
@@ -38,24 +39,76 @@ func TestFields_getFields(t *testing.T) {
 	}
 
 	expression = "* * * * *"
-	f, err = getFields(expression)
+	f, err = getFields(expression, newOptions(nil))
 	if err != nil {
 		t.Errorf("'%s': unexpected error: '%#v'", expression, err)
 	}
 	if f == nil {
 		t.Fatalf("'%s': expected 'fields', got '%#v'", expression, f)
 	}
+	if !reflect.DeepEqual(f.millis.combinations[0].values, []int{0}) {
+		t.Errorf("'%s': expected '%#v', got '%#v'", expression, []int{0}, f.millis.combinations[0].values)
+	}
 	if !reflect.DeepEqual(f.seconds.combinations[0].values, []int{0}) {
 		t.Errorf("'%s': expected '%#v', got '%#v'", expression, []int{0}, f.seconds.combinations[0].values)
 	}
-	if !reflect.DeepEqual(f.year.combinations[0].values, yearValues) {
-		t.Errorf("'%s': expected '%#v', got '%#v'", expression, yearValues, f.seconds.combinations[0].values)
+
+	expression = "*/250 * * * * * * *"
+	f, err = getFields(expression, newOptions(nil))
+	if err != nil {
+		t.Errorf("'%s': unexpected error: '%#v'", expression, err)
+	}
+	if f == nil {
+		t.Fatalf("'%s': expected 'fields', got '%#v'", expression, f)
+	}
+	if !reflect.DeepEqual(f.millis.combinations[0].values, []int{0, 250, 500, 750}) {
+		t.Errorf("'%s': expected '%#v', got '%#v'", expression, []int{0, 250, 500, 750}, f.millis.combinations[0].values)
+	}
+	if !reflect.DeepEqual(f.year.combinations[0].values, []int{DefaultMinYear, 1}) {
+		t.Errorf("'%s': expected '%#v', got '%#v'", expression, []int{DefaultMinYear, 1}, f.year.combinations[0].values)
+	}
+	if f.year.combinations[0].unit != "/" {
+		t.Errorf("'%s': expected '%s', got '%s'", expression, "/", f.year.combinations[0].unit)
+	}
+}
+
+func TestFields_nextYear(t *testing.T) {
+	type testCase struct {
+		expr string
+		opts []Option
+		year int
+		exp  int
+		ok   bool
+	}
+
+	for _, tc := range []testCase{
+		{"* * * * * * *", nil, 2150, 2150, true},
+		{"* * * * * * */5", nil, 2101, 2105, true},
+		{"* * * * * * 2020/10,2105", nil, 2101, 2105, true},
+		{"* * * * * * 2020/10,2105", nil, 2106, 2110, true},
+		{"* * * * * * 2020-2030", nil, 2031, -1, false},
+		{"* * * * * * *", []Option{WithYearRange(2000, 2200)}, 2201, -1, false},
+		{"* * * * * * 2500", []Option{WithYearRange(2000, 3000)}, 2022, 2500, true},
+	} {
+		f, err := getFields(tc.expr, newOptions(tc.opts))
+		if err != nil {
+			t.Fatalf("'%s': unexpected error: %#v", tc.expr, err)
+		}
+
+		if got, ok := f.nextYear(tc.year); got != tc.exp || ok != tc.ok {
+			t.Errorf("'%s': expected '%d/%t', got '%d/%t'", tc.expr, tc.exp, tc.ok, got, ok)
+		}
+	}
+
+	_, err := getFields("* * * * * * 2099", newOptions([]Option{WithYearRange(2100, 2000)}))
+	if eerr := fmt.Sprintf("%s", err); eerr != `invalid year range given '2100-2000'` {
+		t.Errorf("expected '%s', got '%s'", `invalid year range given '2100-2000'`, eerr)
 	}
 }
 
 func TestFields_getFields_InvalidExpression(t *testing.T) {
 	expression := "* * *"
-	f, err := getFields(expression)
+	f, err := getFields(expression, newOptions(nil))
 	if eerr := fmt.Sprintf("%s", err); eerr != `invalid expression given '* * *'` {
 		t.Errorf("'%s': expected '%s', got '%s'", expression, `invalid expression given '* * *'`, eerr)
 	}
@@ -63,16 +116,94 @@ func TestFields_getFields_InvalidExpression(t *testing.T) {
 		t.Errorf("'%s': expected '%#v', got '%#v'", expression, nil, f)
 	}
 
-	expression = "* * * * * * * *"
-	f, err = getFields(expression)
-	if eerr := fmt.Sprintf("%s", err); eerr != `invalid expression given '* * * * * * * *'` {
-		t.Errorf("'%s': expected '%s', got '%s'", expression, `invalid expression given '* * * * * * * *'`, eerr)
+	expression = "* * * * * * * * *"
+	f, err = getFields(expression, newOptions(nil))
+	if eerr := fmt.Sprintf("%s", err); eerr != `invalid expression given '* * * * * * * * *'` {
+		t.Errorf("'%s': expected '%s', got '%s'", expression, `invalid expression given '* * * * * * * * *'`, eerr)
 	}
 	if f != nil {
 		t.Errorf("'%s': expected '%#v', got '%#v'", expression, nil, f)
 	}
 }
 
+func TestFields_getFields_ExtensionFields(t *testing.T) {
+	type testCase struct {
+		expr    string
+		expWeek []int
+		expDoY  []int
+		err     string
+	}
+
+	for _, tc := range []testCase{
+		{"0 0 * * MON week=*/2", []int{1, 3, 5, 7, 9, 11, 13, 15, 17, 19, 21, 23, 25, 27, 29, 31, 33, 35, 37, 39, 41, 43, 45, 47, 49, 51, 53}, nil, ``},
+		{"0 0 0 * * ? * doy=100", nil, []int{100}, ``},
+		{"0 0 0 * * ? * WEEK=1-3 doy=1,366", []int{1, 2, 3}, []int{1, 366}, ``},
+		{"0 0 0 * * ? * week=54", nil, nil, `invalid value in field 'week-of-year' given: '54'`},
+		{"0 0 0 * * ? * doy=L", nil, nil, `the special characters 'L', 'W', '?' and '#' are only allowed in the DoM and DoW fields`},
+		{"0 0 0 * * ? * week=1 week=2", nil, nil, `duplicate extension field given 'week=2'`},
+		{"0 0 0 * * ? * month=1", nil, nil, `unsupported extension field given 'month=1'`},
+	} {
+		f, err := getFields(tc.expr, newOptions(nil))
+		if tc.err != "" || err != nil {
+			if eerr := fmt.Sprintf("%s", err); tc.err != eerr {
+				t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.err, eerr)
+			}
+
+			continue
+		}
+
+		if tc.expWeek != nil && !reflect.DeepEqual(tc.expWeek, f.week.combinations[0].values) {
+			t.Errorf("'%s': expected '%#v', got '%#v'", tc.expr, tc.expWeek, f.week.combinations[0].values)
+		} else if tc.expWeek == nil && f.week != nil {
+			t.Errorf("'%s': expected '%#v', got '%#v'", tc.expr, nil, f.week)
+		}
+
+		if tc.expDoY != nil && !reflect.DeepEqual(tc.expDoY, f.doy.combinations[0].values) {
+			t.Errorf("'%s': expected '%#v', got '%#v'", tc.expr, tc.expDoY, f.doy.combinations[0].values)
+		} else if tc.expDoY == nil && f.doy != nil {
+			t.Errorf("'%s': expected '%#v', got '%#v'", tc.expr, nil, f.doy)
+		}
+	}
+}
+
+func TestFields_getFields_Unsatisfiable(t *testing.T) {
+	type testCase struct {
+		expr string
+		opts []Option
+		err  string
+	}
+
+	never := `the cronjob will never run; the days never occur in the given months and years`
+
+	for _, tc := range []testCase{
+		{"0 0 0 31 2 ? *", nil, never},
+		{"0 0 0 30 FEB ? 2030", nil, never},
+		{"0 0 0 31 4,6,9,11 ? *", nil, never},
+		{"0 0 0 31 4,7 ? *", nil, ``},
+		{"0 0 0 29 2 ? 2023", nil, never},
+		{"0 0 0 29 2 ? 2024", nil, ``},
+		{"0 0 0 29 2 ? 2100/100", []Option{WithYearRange(1970, 2399)}, never},
+		{"0 0 0 29 2 ? 2100/100", nil, ``}, // 2400 is a leap year.
+		{"0 0 0 29 2 ? *", []Option{WithYearRange(2021, 2023)}, never},
+		{"0 0 0 ? 2 1#5 2023", nil, never},
+		{"0 0 0 ? 2 4#5 2024", nil, ``},
+		{"0 0 0 ? * 5L * week=1", nil, never},
+		{"0 0 0 ? * 5#1 * week=1", nil, ``},
+		{"0 0 0 1 1 ? * doy=2", nil, never},
+		{"0 0 0 LW 2 ? *", nil, ``},
+		{"0 0 0 1 1 ? 2020-2021", nil, ``},
+		{"0 0 0 1 1 ? 2020-2021", []Option{WithReferenceTime(time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC))}, ``},
+		{"0 0 0 1 1 ? 1970", nil, ``},
+	} {
+		_, err := getFields(tc.expr, newOptions(tc.opts))
+		if tc.err != "" || err != nil {
+			if eerr := fmt.Sprintf("%s", err); tc.err != eerr {
+				t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.err, eerr)
+			}
+		}
+	}
+}
+
 func TestFields_createFields(t *testing.T) {
 	type testCase struct {
 		parts  []string
@@ -91,7 +222,7 @@ func TestFields_createFields(t *testing.T) {
 		{[]string{"1", "1", "1", "?", "1", "?", "*"}, false, `the cronjob will never run; both DoM and DoW contain the special character '?'`},
 		{[]string{"1", "1", "1", "1", "1", "1", "*"}, true, ``},
 	} {
-		fields, err := createFields(tc.parts)
+		fields, err := createFields(tc.parts, newOptions(nil))
 		if tc.fields && fields == nil {
 			t.Errorf("'%#v': expected 'fields', got '%#v'", tc.parts, fields)
 		} else if !tc.fields && fields != nil {
@@ -126,7 +257,7 @@ func TestFields_createField(t *testing.T) {
 		{"mon-3,5", typeDoW, true, "", []int{1, 2, 3, 5}, ``},
 		{"mon-3,5-7", typeDoW, true, "", []int{0, 1, 2, 3, 5, 6}, ``},
 	} {
-		field, err := createField(tc.expr, tc.ft)
+		field, err := createField(tc.expr, tc.ft, newOptions(nil))
 		if tc.field && field == nil {
 			t.Errorf("'%#v': expected 'fields', got '%#v'", tc.expr, field)
 		} else if !tc.field && field != nil {
@@ -157,7 +288,7 @@ func TestFields_createField(t *testing.T) {
 }
 
 func TestFields_mergeCombinations(t *testing.T) {
-	field, err := createField("1", typeDoM)
+	field, err := createField("1", typeDoM, newOptions(nil))
 	if err != nil {
 		t.Fatalf("%#v", err)
 	}
@@ -168,7 +299,7 @@ func TestFields_mergeCombinations(t *testing.T) {
 		t.Errorf("expected '%#v', got '%#v'", []int{1}, field.combinations[0].values)
 	}
 
-	field, err = createField("L,1,L,5", typeDoM)
+	field, err = createField("L,1,L,5", typeDoM, newOptions(nil))
 	if err != nil {
 		t.Fatalf("%#v", err)
 	}
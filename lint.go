@@ -0,0 +1,314 @@
+// Copyright 2022 Alex Schneider. All rights reserved.
+
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Warning represents a suspicious part of a valid expression, see <cron.Lint>.
+type Warning struct {
+	// Position contains the byte offset of the suspicious field in the
+	// expression or -1 if the position is not known.
+	Position int
+
+	// Message describes the suspicious part of the expression.
+	Message string
+}
+
+// lintField represents a field of an expression, which is checked by <cron.Lint>.
+type lintField struct {
+	field    *field
+	ft       fieldType
+	position int
+}
+
+/* ==================================================================================================== */
+
+// String implements the <fmt.Stringer> interface.
+func (w Warning) String() string {
+	if w.Position < 0 {
+		return w.Message
+	}
+
+	return fmt.Sprintf("position %d: %s", w.Position, w.Message)
+}
+
+/* ==================================================================================================== */
+
+// Lint returns the warnings about the suspicious parts of the given expression, which is
+// valid otherwise. The parse error is returned for invalid expressions. The following parts
+// are reported:
+//   - the `*` in the seconds field, which runs the cronjob every second.
+//   - the `*` in the DoM or DoW field, which disables the restriction of the other field.
+//   - the ranges, which wrap around the end of the field, e.g. `FRI-MON`.
+//   - the steps, which are larger than the range of the field, e.g. `*/90`.
+//   - the `R` in the year field, which runs the cronjob in a single random year.
+//   - the day fields, which match in less than one of ten years, e.g. `0 0 0 ? 2 1#5 *`.
+//   - the year field, which matches in the years before the reference time only.
+//   - the hours, which are skipped or repeated by the daylight saving time changes in the
+//     location of the expression, see <cron.WithLocation>.
+//
+// The positions are not known for the expressions converted from macros or other dialects.
+// The recurrence rules and the rate expressions are not checked.
+func Lint(expression string, opts ...Option) ([]Warning, error) {
+	o := newOptions(opts)
+
+	sp, err := parseSpec(expression, o)
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings []Warning
+
+	for _, leaf := range leafSchedules(sp) {
+		warnings = append(warnings, leaf.lint(o)...)
+	}
+
+	return warnings, nil
+}
+
+// leafSchedules returns the leaf schedules of the given spec in the order of the expression.
+func leafSchedules(sp spec) []*schedule {
+	switch sp := sp.(type) {
+	case *schedule:
+		if sp.spec != nil {
+			return leafSchedules(sp.spec)
+		} else if sp.fields.once {
+			return nil
+		}
+
+		return []*schedule{sp}
+	case union:
+		var leaves []*schedule
+
+		for _, operand := range sp {
+			leaves = append(leaves, leafSchedules(operand)...)
+		}
+
+		return leaves
+	case intersection:
+		var leaves []*schedule
+
+		for _, operand := range sp {
+			leaves = append(leaves, leafSchedules(operand)...)
+		}
+
+		return leaves
+	case *exception:
+		return append(leafSchedules(sp.spec), leafSchedules(sp.excluded)...)
+	}
+
+	return nil
+}
+
+func (s *schedule) lint(o *options) []Warning {
+	fs := s.fields
+	lintFields := fs.lintFields(s.offset)
+
+	var warnings []Warning
+
+	warn := func(lf lintField, format string, args ...interface{}) {
+		warnings = append(warnings, Warning{Position: lf.position, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, lf := range lintFields {
+		switch {
+		case lf.ft == typeSeconds && lf.field.expression == "*" && lf.position >= 0:
+			warn(lf, "the '*' in the field '%s' runs the cronjob every second", lf.ft)
+		case (lf.ft == typeDoM || lf.ft == typeDoW) && lf.field.expression == "*" && fs.dayMode == dayModeUnion:
+			other := typeDoW
+			if lf.ft == typeDoW {
+				other = typeDoM
+			}
+
+			if isRestricted(fs.dom) || isRestricted(fs.dow) {
+				warn(lf, "the '*' in the field '%s' matches every day, so that the field '%s' has no effect; use '?' instead", lf.ft, other)
+			}
+		}
+
+		for _, expr := range strings.Split(strings.ToUpper(lf.field.expression), ",") {
+			if message := lintItem(expr, lf.ft, o); message != "" {
+				warn(lf, "%s", message)
+			}
+		}
+	}
+
+	// The years are counted like by the check at parse time, but from the year of the reference
+	// time on, see <cron.WithReferenceTime>.
+	now := o.now
+	if fs.location != nil {
+		now = now.In(fs.location)
+	}
+
+	if matching, total := fs.matchingYears(now.Year(), false); matching == 0 {
+		warn(lintFields[6], "the cronjob runs only in the years before %d, so that it never runs again", now.Year())
+	} else if matching*10 < total {
+		lf := lintFields[3]
+		if isRestricted(fs.dow) {
+			lf = lintFields[5]
+		}
+
+		warn(lf, "the days match in %d of %d years only, so that the cronjob runs rarely", matching, total)
+	}
+
+	if fs.location != nil {
+		// The changes of the year of the reference time are checked, see <cron.WithReferenceTime>.
+		hours := dstHours(fs.location, o.now.In(fs.location).Year())
+
+		var affected []string
+
+		for h, ok := hours.next(0); ok; h, ok = hours.next(h + 1) {
+			if fs.hours.bits.has(h) {
+				affected = append(affected, strconv.Itoa(h))
+			}
+		}
+
+		if len(affected) > 0 {
+			warn(lintFields[2], "the hours %s are affected by the daylight saving time changes in '%s', so that the cronjob may be skipped or run twice", strings.Join(affected, ","), fs.location)
+		}
+	}
+
+	return warnings
+}
+
+// lintFields returns the fields of the expression with their positions, the first fields
+// are always the seconds, the minutes and the hours.
+func (fs *fields) lintFields(offset int) []lintField {
+	var parts []string
+	if fs.source != "" {
+		parts = reFieldsMatcher.FindAllString(fs.source, -1)
+	}
+
+	fieldsParts, extensionParts := splitExtensionFields(parts)
+	fieldsCount := len(fieldsParts)
+
+	// The fields are counted from the milliseconds on in the 8 fields form.
+	first := -1
+	if fieldsCount == 8 {
+		first = 0
+	}
+
+	position := func(index int, extension bool) int {
+		if fs.source == "" {
+			return -1
+		} else if p := fieldPosition(fs.source, index, extension, fieldsCount); p >= 0 {
+			return offset + p
+		}
+
+		return -1
+	}
+
+	lintFields := []lintField{
+		{fs.seconds, typeSeconds, position(first+1, false)},
+		{fs.minutes, typeMinutes, position(first+2, false)},
+		{fs.hours, typeHours, position(first+3, false)},
+		{fs.dom, typeDoM, position(first+4, false)},
+		{fs.month, typeMonth, position(first+5, false)},
+		{fs.dow, typeDoW, position(first+6, false)},
+		{fs.year, typeYear, position(first+7, false)},
+		{fs.millis, typeMilliseconds, position(first, false)},
+	}
+
+	for i, part := range extensionParts {
+		name, _, _ := strings.Cut(part, "=")
+
+		switch ft := extensionFields[strings.ToUpper(name)]; ft {
+		case typeWeek:
+			lintFields = append(lintFields, lintField{fs.week, ft, position(i, true)})
+		case typeDoY:
+			lintFields = append(lintFields, lintField{fs.doy, ft, position(i, true)})
+		}
+	}
+
+	return lintFields
+}
+
+// lintItem returns the warning about a single item of a field or an empty string.
+func lintItem(expr string, ft fieldType, o *options) string {
+	min, max := getMinMax(ft, o)
+
+	if ft == typeYear && (expr == "R" || strings.HasSuffix(expr, "/R")) {
+		return fmt.Sprintf("the 'R' in the field '%s' selects a single random year, so that the cronjob runs in one year only", ft)
+	}
+
+	var step string
+
+	if matches := reWildcardIntervalValue.FindStringSubmatch(expr); len(matches) == 2 {
+		step = matches[1]
+	} else if matches := reSingleValueIntervalValue.FindStringSubmatch(expr); len(matches) == 3 {
+		step = matches[2]
+	} else if matches := reRangeIntervalValue.FindStringSubmatch(expr); len(matches) == 4 {
+		step = matches[3]
+	}
+
+	if n, err := strconv.Atoi(step); err == nil && n > max-min {
+		return fmt.Sprintf("the step '%d' in the field '%s' is larger than the range of the field, so that only a single value matches", n, ft)
+	}
+
+	value, _, _ := strings.Cut(expr, "/")
+	if matches := reRangeValue.FindStringSubmatch(value); len(matches) == 3 && ft != typeYear {
+		v1, err1 := getSingleValue(matches[1], ft, o)
+		v2, err2 := getSingleValue(matches[2], ft, o)
+
+		// The Sunday is the upper end of the ranges like `SAT-SUN` or `5-7`.
+		if ft == typeDoW && err2 == nil && (matches[2] == "7" || matches[2] == "SUN") {
+			v2[0] = 7
+		}
+
+		if err1 == nil && err2 == nil && v1[0] > v2[0] {
+			return fmt.Sprintf("the range '%s' in the field '%s' wraps around the end of the field", value, ft)
+		}
+	}
+
+	return ""
+}
+
+// isRestricted reports whether the given day field restricts the days.
+func isRestricted(f *field) bool {
+	return f.expression != "*" && f.expression != "?"
+}
+
+// dstHours returns the hours of the day, which are skipped or repeated by the daylight saving
+// time changes of the given location in the given year.
+func dstHours(loc *time.Location, year int) bitset {
+	hours := newBitset(nil, 23)
+
+	end := time.Date(year+1, 1, 1, 0, 0, 0, 0, time.UTC)
+	_, prev := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC).In(loc).Zone()
+
+	for t := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC); t.Before(end); t = t.Add(time.Hour) {
+		_, offset := t.In(loc).Zone()
+		if offset == prev {
+			continue
+		}
+
+		// Finds the exact time of the change within the last hour.
+		lo, hi := t.Add(-time.Hour), t
+		for hi.Sub(lo) > time.Minute {
+			mid := lo.Add(hi.Sub(lo) / 2)
+			if _, o := mid.In(loc).Zone(); o == prev {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+
+		// The wall clocks between both offsets are skipped or repeated.
+		from, to := hi.Add(time.Duration(prev)*time.Second), hi.Add(time.Duration(offset)*time.Second)
+		if to.Before(from) {
+			from, to = to, from
+		}
+
+		for w := from.Truncate(time.Hour); w.Before(to); w = w.Add(time.Hour) {
+			hours[0] |= 1 << w.Hour()
+		}
+
+		prev = offset
+	}
+
+	return hours
+}
@@ -0,0 +1,95 @@
+package cron
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestLint_Lint(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	type testCase struct {
+		expr string
+		opts []Option
+		exp  string
+	}
+
+	for _, tc := range []testCase{
+		{"0 0 9 ? * MON-FRI *", nil, `[]`},
+		{"* * * * * ? *", nil, `[position 0: the '*' in the field 'seconds' runs the cronjob every second]`},
+		{"* * * * *", nil, `[]`},
+		{"0 0 9 * * MON *", nil, `[position 6: the '*' in the field 'day-of-month' matches every day, so that the field 'day-of-week' has no effect; use '?' instead]`},
+		{"0 9 15 * *", nil, `[position 9: the '*' in the field 'day-of-week' matches every day, so that the field 'day-of-month' has no effect; use '?' instead]`},
+		{"0 9 * * MON", []Option{WithDialect(DialectVixie)}, `[]`},
+		{"0 0 9 ? * FRI-MON *", nil, `[position 10: the range 'FRI-MON' in the field 'day-of-week' wraps around the end of the field]`},
+		{"0 */90 * * * ? *", nil, `[position 2: the step '90' in the field 'minutes' is larger than the range of the field, so that only a single value matches]`},
+		{"0 0 9 1 1 ? R", nil, `[position 12: the 'R' in the field 'year' selects a single random year, so that the cronjob runs in one year only]`},
+		{"0 0 0 * * ? * week=40-10", nil, `[position 14: the range '40-10' in the field 'week-of-year' wraps around the end of the field]`},
+		{"0 0 9 ? * MON * | 0 */90 * * * ? *", nil, `[position 20: the step '90' in the field 'minutes' is larger than the range of the field, so that only a single value matches]`},
+		{"@daily", nil, `[]`},
+		{"@reboot", nil, `[]`},
+		{"0 */90 * ? * *", []Option{WithDialect(DialectQuartz)}, `[the step '90' in the field 'minutes' is larger than the range of the field, so that only a single value matches]`},
+		{"0 30 2 ? * SUN *", []Option{WithLocation(berlin)}, `[position 5: the hours 2 are affected by the daylight saving time changes in 'Europe/Berlin', so that the cronjob may be skipped or run twice]`},
+		{"0 30 1-3 ? * SUN *", []Option{WithLocation(time.UTC)}, `[]`},
+		{"0 30 2 ? * SUN *", nil, `[]`},
+		{"0 0 9 ? * SAT-SUN *", nil, `[]`},
+		{"0 0 0 ? 2 1#5 *", nil, `[position 10: the days match in 15 of 400 years only, so that the cronjob runs rarely]`},
+		{"0 0 0 29 2 ? *", nil, `[]`},
+		{"0 0 0 1 1 ? 2020-2021", nil, `[position 12: the cronjob runs only in the years before 2022, so that it never runs again]`},
+		{"0 0 0 1 1 ? 2020-2022", nil, `[]`},
+		{"Fri *-*-13 00:00:00", []Option{WithDialect(DialectSystemd)}, `[]`},
+		{"0 0 9 ? * 5-7 *", nil, `[]`},
+		{"0 0 9 ? * SUN-MON *", nil, `[]`},
+		{"0 0 0 1 1 ? 2030/50", []Option{WithYearRange(2030, 2070)}, `[position 12: the step '50' in the field 'year' is larger than the range of the field, so that only a single value matches]`},
+		{"0 0 0 1 1 ? 2030/50", nil, `[]`},
+		{"0 0 * ? * SUN *", []Option{WithLocation(saoPaulo), WithReferenceTime(time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC))}, `[position 4: the hours 0,23 are affected by the daylight saving time changes in 'America/Sao_Paulo', so that the cronjob may be skipped or run twice]`},
+		{"0 0 * ? * SUN *", []Option{WithLocation(saoPaulo), WithReferenceTime(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))}, `[]`},
+	} {
+		warnings, err := Lint(tc.expr, append([]Option{WithReferenceTime(testScheduleTime)}, tc.opts...)...)
+		if err != nil {
+			t.Errorf("'%s': unexpected error '%s'", tc.expr, err)
+
+			continue
+		}
+
+		if got := fmt.Sprint(warnings); got != tc.exp {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.exp, got)
+		}
+	}
+
+	if _, err := Lint("0 0 25 * * ? *"); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestLint_dstHours(t *testing.T) {
+	type testCase struct {
+		loc string
+		exp string
+	}
+
+	for _, tc := range []testCase{
+		{"UTC", "[]"},
+		{"Europe/Berlin", "[2]"},
+		{"America/New_York", "[1 2]"},
+		{"Australia/Lord_Howe", "[1 2]"},
+	} {
+		loc, err := time.LoadLocation(tc.loc)
+		if err != nil {
+			t.Skip(err)
+		}
+
+		if got := fmt.Sprint(dstHours(loc, 2023).values()); got != tc.exp {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.loc, tc.exp, got)
+		}
+	}
+}
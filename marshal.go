@@ -0,0 +1,145 @@
+// Copyright 2022 Alex Schneider. All rights reserved.
+
+package cron
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// MarshalText implements the <encoding.TextMarshaler> interface. The schedule is
+// encoded as the original expression, the zero value as an empty text. The expressions
+// of the other dialects are encoded in the normalized native form, see <cron.Normalize>,
+// so that they are executed at the same times after decoding. An error is returned for
+// the recurrence rules, the rate expressions and the expressions with their own timezone,
+// which cannot be represented in the native syntax.
+func (s Schedule) MarshalText() ([]byte, error) {
+	text, err := s.nativeExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	return []byte(text), nil
+}
+
+// UnmarshalText implements the <encoding.TextUnmarshaler> interface, which is also
+// used by the most YAML and TOML decoders. The expression is parsed with the default
+// options, so that the expressions of the other dialects are not supported. An empty
+// text resets the schedule to the zero value.
+func (s *Schedule) UnmarshalText(text []byte) error {
+	if len(bytes.TrimSpace(text)) == 0 {
+		*s = Schedule{}
+
+		return nil
+	}
+
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+
+	*s = *parsed
+
+	return nil
+}
+
+// MarshalJSON implements the <json.Marshaler> interface. The schedule is encoded as
+// a JSON string containing the expression of <cron.Schedule.MarshalText>.
+func (s Schedule) MarshalJSON() ([]byte, error) {
+	text, err := s.nativeExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(text)
+}
+
+// UnmarshalJSON implements the <json.Unmarshaler> interface. The JSON `null` leaves
+// the schedule unchanged.
+func (s *Schedule) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	var expression string
+	if err := json.Unmarshal(data, &expression); err != nil {
+		return err
+	}
+
+	return s.UnmarshalText([]byte(expression))
+}
+
+// Scan implements the <sql.Scanner> interface. The column has to contain the expression as
+// a string. The NULL values and the empty strings are scanned as the zero value.
+func (s *Schedule) Scan(src interface{}) error {
+	switch src := src.(type) {
+	case string:
+		return s.UnmarshalText([]byte(src))
+	case []byte:
+		return s.UnmarshalText(src)
+	case nil:
+		*s = Schedule{}
+
+		return nil
+	}
+
+	return fmt.Errorf("unsupported type given '%T'", src)
+}
+
+// Value implements the <driver.Valuer> interface. The schedule is stored as the expression
+// of <cron.Schedule.MarshalText>, so that <cron.Schedule.Scan> reads it with the same meaning.
+// The zero value is stored as NULL.
+func (s Schedule) Value() (driver.Value, error) {
+	if s.IsZero() {
+		return nil, nil
+	}
+
+	return s.nativeExpression()
+}
+
+// Set implements the <flag.Value> interface. The position of an invalid field is appended
+// to the error, because the flag packages print the error message only.
+func (s *Schedule) Set(value string) error {
+	err := s.UnmarshalText([]byte(value))
+
+	var pe *ParseError
+	if errors.As(err, &pe) && pe.Position >= 0 {
+		return fmt.Errorf("%w at position %d", err, pe.Position)
+	}
+
+	return err
+}
+
+// Type returns the type name of the flag value, which is required by the `pflag` package.
+func (s *Schedule) Type() string {
+	return "schedule"
+}
+
+/* ==================================================================================================== */
+
+// nativeExpression returns the expression of the schedule in the native syntax, which is
+// parsed with the same meaning by <cron.Parse> with the default options.
+func (s Schedule) nativeExpression() (string, error) {
+	if s.IsZero() || s.native {
+		return s.String(), nil
+	}
+
+	var err error
+
+	expression, walkErr := walkSpec(s.spec, func(leaf *schedule) string {
+		if leaf.fields.zoned && err == nil {
+			err = fmt.Errorf("the timezone of the expression '%s' cannot be encoded", s.expression)
+		}
+
+		return leaf.fields.String()
+	}, " | ", " & ", " ! ")
+
+	if walkErr != nil {
+		return "", fmt.Errorf("the expression '%s' cannot be encoded in the native syntax", s.expression)
+	}
+
+	return expression, err
+}
@@ -0,0 +1,292 @@
+package cron_test
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/alex-schneider/cron"
+)
+
+func TestSchedule_MarshalText(t *testing.T) {
+	s, err := cron.Parse("  0 0 9 ? * MON-FRI *  ")
+	if err != nil {
+		t.Fatalf("unexpected error '%s'", err)
+	}
+
+	text, err := s.MarshalText()
+	if err != nil {
+		t.Fatalf("unexpected error '%s'", err)
+	}
+
+	if exp := "0 0 9 ? * MON-FRI *"; string(text) != exp {
+		t.Errorf("expected '%s', got '%s'", exp, text)
+	}
+
+	var got cron.Schedule
+	if err := got.UnmarshalText(text); err != nil {
+		t.Fatalf("unexpected error '%s'", err)
+	}
+
+	ref := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	if got.Next(ref) != s.Next(ref) {
+		t.Errorf("expected '%s', got '%s'", s.Next(ref), got.Next(ref))
+	}
+
+	var pe *cron.ParseError
+	if err := got.UnmarshalText([]byte("0 0 25 * * ? *")); !errors.As(err, &pe) || pe.Position != 4 {
+		t.Errorf("expected '%T' at position 4, got '%#v'", pe, err)
+	}
+
+	if got.String() != "0 0 9 ? * MON-FRI *" {
+		t.Errorf("expected unchanged schedule, got '%s'", got.String())
+	}
+}
+
+func TestSchedule_MarshalText_Dialects(t *testing.T) {
+	type testCase struct {
+		expr   string
+		opts   []cron.Option
+		exp    string
+		expErr string
+	}
+
+	for _, tc := range []testCase{
+		{"0 9 * * MON", []cron.Option{cron.WithDialect(cron.DialectVixie)}, "0 0 9 ? * MON *", ""},
+		{"0 0 */2 * MON", []cron.Option{cron.WithDialect(cron.DialectVixie)}, "0 0 0 */2 * ? * & 0 0 0 ? * MON *", ""},
+		{"0 0 12 ? * 2 *", []cron.Option{cron.WithDialect(cron.DialectQuartz)}, "0 0 12 ? * 1 *", ""},
+		{"Mon *-*-01 00:00:00", []cron.Option{cron.WithDialect(cron.DialectSystemd)}, "0 0 0 1 * ? * & 0 0 0 ? * MON *", ""},
+		{"*-*-* 09:00:00 Europe/Berlin", []cron.Option{cron.WithDialect(cron.DialectSystemd)}, "", "the timezone of the expression '*-*-* 09:00:00 Europe/Berlin' cannot be encoded"},
+		{"FREQ=DAILY", []cron.Option{cron.WithDialect(cron.DialectRRule)}, "", "the expression 'FREQ=DAILY' cannot be encoded in the native syntax"},
+		{"rate(5 minutes)", []cron.Option{cron.WithDialect(cron.DialectEventBridge)}, "", "the expression 'rate(5 minutes)' cannot be encoded in the native syntax"},
+		{"@daily", nil, "@daily", ""},
+	} {
+		s, err := cron.Parse(tc.expr, tc.opts...)
+		if err != nil {
+			t.Fatalf("'%s': unexpected error '%s'", tc.expr, err)
+		}
+
+		text, err := s.MarshalText()
+		if tc.expErr != "" {
+			if err == nil || err.Error() != tc.expErr {
+				t.Errorf("'%s': expected '%s', got '%v'", tc.expr, tc.expErr, err)
+			}
+
+			continue
+		} else if string(text) != tc.exp {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.exp, text)
+		}
+
+		var got cron.Schedule
+		if err := got.UnmarshalText(text); err != nil {
+			t.Fatalf("'%s': unexpected error '%s'", tc.expr, err)
+		}
+
+		// The decoded schedule is executed at the same times.
+		for ref, i := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), 0; i < 5; i++ {
+			next := s.Next(ref)
+			if decoded := got.Next(ref); !decoded.Equal(next) {
+				t.Errorf("'%s': expected '%s', got '%s'", tc.expr, next, decoded)
+			}
+
+			ref = next
+		}
+	}
+
+	// The schedules are encoded by value, too.
+	s, err := cron.Parse("0 9 * * MON", cron.WithDialect(cron.DialectVixie))
+	if err != nil {
+		t.Fatalf("unexpected error '%s'", err)
+	}
+
+	data, err := json.Marshal(struct{ Backup cron.Schedule }{*s})
+	if exp := `{"Backup":"0 0 9 ? * MON *"}`; err != nil || string(data) != exp {
+		t.Errorf("expected '%s', got '%s' (%v)", exp, data, err)
+	}
+}
+
+func TestSchedule_MarshalJSON(t *testing.T) {
+	type config struct {
+		Backup  cron.Schedule  `json:"backup"`
+		Cleanup *cron.Schedule `json:"cleanup"`
+		Reports cron.Schedule  `json:"reports"`
+	}
+
+	var c config
+	if err := json.Unmarshal([]byte(`{"backup":"@daily","cleanup":"0 */5 * * *","reports":null}`), &c); err != nil {
+		t.Fatalf("unexpected error '%s'", err)
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		t.Fatalf("unexpected error '%s'", err)
+	}
+
+	exp := `{"backup":"@daily","cleanup":"0 */5 * * *","reports":""}`
+	if string(data) != exp {
+		t.Errorf("expected '%s', got '%s'", exp, data)
+	}
+
+	expErr := "invalid value in field 'hours' given: '25'"
+	if err := json.Unmarshal([]byte(`{"backup":"0 0 25 * * ? *"}`), &c); err == nil || err.Error() != expErr {
+		t.Errorf("expected '%s', got '%v'", expErr, err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"backup":42}`), &c); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestSchedule_Scan(t *testing.T) {
+	type testCase struct {
+		src    interface{}
+		exp    string
+		expErr string
+	}
+
+	tests := []testCase{
+		{"@hourly", "@hourly", ""},
+		{[]byte("0 0 9 ? * MON *"), "0 0 9 ? * MON *", ""},
+		{"0 0 25 * * ? *", "", "invalid value in field 'hours' given: '25'"},
+		{nil, "", ""},
+		{"", "", ""},
+		{int64(42), "", "unsupported type given 'int64'"},
+	}
+
+	for _, tc := range tests {
+		var s cron.Schedule
+
+		err := s.Scan(tc.src)
+		if tc.expErr != "" {
+			if err == nil || err.Error() != tc.expErr {
+				t.Errorf("'%v': expected '%s', got '%v'", tc.src, tc.expErr, err)
+			}
+
+			continue
+		} else if err != nil {
+			t.Errorf("'%v': unexpected error '%s'", tc.src, err)
+
+			continue
+		}
+
+		var exp interface{} = tc.exp
+		if tc.exp == "" {
+			exp = nil
+		}
+
+		value, err := s.Value()
+		if err != nil {
+			t.Errorf("'%v': unexpected error '%s'", tc.src, err)
+		} else if value != exp {
+			t.Errorf("'%v': expected '%s', got '%v'", tc.src, tc.exp, value)
+		}
+	}
+
+	// The rows written from the schedules of the other dialects are read with the same meaning.
+	s, err := cron.Parse("0 0 12 ? * 2 *", cron.WithDialect(cron.DialectQuartz))
+	if err != nil {
+		t.Fatalf("unexpected error '%s'", err)
+	}
+
+	value, err := s.Value()
+	if exp := "0 0 12 ? * 1 *"; err != nil || value != exp {
+		t.Errorf("expected '%s', got '%v' (%v)", exp, value, err)
+	}
+
+	var got cron.Schedule
+	if err := got.Scan(value); err != nil {
+		t.Fatalf("unexpected error '%s'", err)
+	}
+
+	if monday := time.Date(2023, 1, 2, 12, 0, 0, 0, time.UTC); !got.Matches(monday) {
+		t.Errorf("expected a match at '%s'", monday)
+	}
+
+	rate, err := cron.Parse("rate(5 minutes)", cron.WithDialect(cron.DialectEventBridge))
+	if err != nil {
+		t.Fatalf("unexpected error '%s'", err)
+	}
+
+	if _, err := rate.Value(); err == nil {
+		t.Errorf("expected error, got nil")
+	}
+}
+
+func TestSchedule_Zero(t *testing.T) {
+	var s cron.Schedule
+
+	ref := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	if !s.IsZero() || !s.Next(ref).IsZero() || s.Matches(ref) {
+		t.Errorf("expected a schedule without executions, got '%s'", s.Next(ref))
+	}
+
+	other, err := cron.Parse("@daily")
+	if err != nil {
+		t.Fatalf("unexpected error '%s'", err)
+	}
+
+	if !other.Covers(&s) || s.Covers(other) || !s.Equal(&cron.Schedule{}) {
+		t.Errorf("expected the zero value to be covered by all schedules")
+	}
+
+	text, err := s.MarshalText()
+	if err != nil || string(text) != "" {
+		t.Errorf("expected '%s', got '%s' (%v)", "", text, err)
+	}
+
+	if err := other.UnmarshalText(text); err != nil || !other.IsZero() {
+		t.Errorf("expected the zero value, got '%s' (%v)", other, err)
+	}
+
+	data, err := json.Marshal(struct{ S *cron.Schedule }{&s})
+	if exp := `{"S":""}`; err != nil || string(data) != exp {
+		t.Errorf("expected '%s', got '%s' (%v)", exp, data, err)
+	}
+
+	var c struct{ S, N *cron.Schedule }
+	if err := json.Unmarshal([]byte(`{"S":"","N":null}`), &c); err != nil || !c.S.IsZero() || c.N != nil {
+		t.Errorf("expected the zero values, got '%v' (%v)", c, err)
+	}
+
+	if value, err := c.S.Value(); err != nil || value != nil {
+		t.Errorf("expected NULL, got '%v' (%v)", value, err)
+	}
+}
+
+func TestSchedule_Set(t *testing.T) {
+	var s cron.Schedule
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(&s, "schedule", "the schedule")
+
+	if err := fs.Parse([]string{"--schedule", "@hourly"}); err != nil {
+		t.Fatalf("unexpected error '%s'", err)
+	}
+
+	if s.String() != "@hourly" {
+		t.Errorf("expected '%s', got '%s'", "@hourly", s.String())
+	}
+
+	expErr := `invalid value "0 0 25 * * ? *" for flag -schedule: invalid value in field 'hours' given: '25' at position 4`
+	if err := fs.Parse([]string{"--schedule", "0 0 25 * * ? *"}); err == nil || err.Error() != expErr {
+		t.Errorf("expected '%s', got '%v'", expErr, err)
+	}
+
+	var pe *cron.ParseError
+	if err := s.Set("0 0 25 * * ? *"); !errors.As(err, &pe) {
+		t.Errorf("expected '%T', got '%#v'", pe, err)
+	}
+
+	expErr = "unsupported macro given '@foo'"
+	if err := s.Set("@foo"); err == nil || err.Error() != expErr {
+		t.Errorf("expected '%s', got '%v'", expErr, err)
+	}
+
+	if s.Type() != "schedule" {
+		t.Errorf("expected '%s', got '%s'", "schedule", s.Type())
+	}
+}
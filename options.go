@@ -0,0 +1,121 @@
+// Copyright 2022 Alex Schneider. All rights reserved.
+
+package cron
+
+import "time"
+
+// Option configures the parsing and the execution of a cron expression.
+type Option func(*options)
+
+const (
+	// DefaultMinYear is the default lower bound of the year field.
+	DefaultMinYear = 1970
+	// DefaultMaxYear is the default upper bound of the year field.
+	DefaultMaxYear = 9999
+)
+
+// options contains all settings which can be configured by an <cron.Option>.
+type options struct {
+	dialect    Dialect
+	calendar   Calendar
+	location   *time.Location
+	minYear    int
+	maxYear    int
+	userColumn bool
+	now        time.Time // Reference time of the `.` special character.
+	hasNow     bool      // Whether the reference time is given explicitly.
+	name       string
+	legacyJobs bool
+}
+
+/* ==================================================================================================== */
+
+// WithDialect selects the syntax of the parsed expressions. Defaults to <cron.DialectNative>.
+func WithDialect(dialect Dialect) Option {
+	return func(o *options) {
+		o.dialect = dialect
+	}
+}
+
+// WithCalendar excludes all days of the given calendar from the execution.
+// The excluded days are also treated as non-business days by the `W` and
+// `LW` special characters.
+func WithCalendar(calendar Calendar) Option {
+	return func(o *options) {
+		o.calendar = calendar
+	}
+}
+
+// WithYearRange overrides the allowed range of the year field. Defaults to
+// <cron.DefaultMinYear> and <cron.DefaultMaxYear>. Open-ended values of the
+// year field like `*` or `*/5` match any year up to the upper bound.
+func WithYearRange(min, max int) Option {
+	return func(o *options) {
+		o.minYear = min
+		o.maxYear = max
+	}
+}
+
+// WithLocation calculates the execution times in the given location instead of the
+// location of the reference time. A timezone of the expression itself, e.g. of the
+// <cron.DialectSystemd>, takes precedence.
+func WithLocation(location *time.Location) Option {
+	return func(o *options) {
+		o.location = location
+	}
+}
+
+// WithUserColumn expects the user column between the expression and the command of the
+// crontab entries, like in the system crontabs `/etc/crontab` and `/etc/cron.d/*`.
+func WithUserColumn() Option {
+	return func(o *options) {
+		o.userColumn = true
+	}
+}
+
+// WithReferenceTime sets the reference time of the `.` special character, which is replaced
+// by the value of the reference time in each field, e.g. `0 0 . * * ? *` runs daily at the
+// hour of the reference time in the location of <cron.WithLocation>. The EventBridge rate
+// expressions are anchored at the reference time, the default DTSTART of the recurrence rules
+// is its day, the `R` special character in the year field picks one of the 10 years from its
+// year on, and the past years are linted from its year on. Defaults to the start time of the
+// program.
+func WithReferenceTime(t time.Time) Option {
+	return func(o *options) {
+		o.now = t
+		o.hasNow = true
+	}
+}
+
+// WithName sets the name of the schedule, which is passed to the recipient in each
+// <cron.Job> of the <cron.NewJobCh> channel.
+func WithName(name string) Option {
+	return func(o *options) {
+		o.name = name
+	}
+}
+
+// WithLegacyJobs restores the former events of the <cron.NewJobCh> channel. No event with
+// <cron.StateScheduled> is sent, and the last execution is sent with <cron.StateNoMatches>
+// instead of being followed by a separate event.
+func WithLegacyJobs() Option {
+	return func(o *options) {
+		o.legacyJobs = true
+	}
+}
+
+/* ==================================================================================================== */
+
+func newOptions(opts []Option) *options {
+	o := &options{
+		minYear: DefaultMinYear,
+		maxYear: DefaultMaxYear,
+		now:     startupTime,
+	}
+
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
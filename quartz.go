@@ -0,0 +1,188 @@
+// Copyright 2022 Alex Schneider. All rights reserved.
+
+package cron
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	reDoWPart         = regexp.MustCompile(`^(\w+)(-(\w+))?(/(\d+|R))?$`)
+	reDoWSpecialPart  = regexp.MustCompile(`^(\w+)(L|#\d+)$`)
+	reDoWWildcardPart = regexp.MustCompile(`^\*(/\d+)?$`)
+)
+
+/* ==================================================================================================== */
+
+// FromQuartz converts the given Quartz expression to the 7 fields syntax of this package.
+func FromQuartz(expression string) (string, error) {
+	fieldsParts := reFieldsMatcher.FindAllString(expression, -1)
+	if len(fieldsParts) < 6 || len(fieldsParts) > 7 {
+		return "", fmt.Errorf("invalid Quartz expression given '%s'", expression)
+	} else if len(fieldsParts) == 6 {
+		fieldsParts = append(fieldsParts, "*")
+	}
+
+	if err := checkQuartzFields(fieldsParts); err != nil {
+		return "", err
+	}
+
+	if (fieldsParts[3] == "?") == (fieldsParts[5] == "?") {
+		return "", fmt.Errorf("the special character '?' is required in either the DoM or the DoW field of Quartz expressions")
+	}
+
+	dow, err := mapDoWField(fieldsParts[5], func(v int) (int, bool) {
+		return v - 1, v >= 1 && v <= 7
+	})
+	if err != nil {
+		return "", err
+	}
+
+	fieldsParts[5] = dow
+
+	return strings.Join(fieldsParts, " "), nil
+}
+
+// ToQuartz converts the given expression of this package to the Quartz syntax.
+func ToQuartz(expression string) (string, error) {
+	expression = strings.TrimSpace(expression)
+
+	e, err := expressionFromMacro(expression)
+	if err != nil {
+		return "", err
+	} else if e == "~" {
+		return "", fmt.Errorf("the macro '@reboot' is not supported in Quartz expressions")
+	} else if e != "" {
+		expression = e
+	}
+
+	if isCompositeExpression(expression) {
+		return "", fmt.Errorf("composite expressions are not supported in Quartz expressions")
+	}
+
+	if _, err := getFields(expression, newOptions(nil)); err != nil {
+		return "", err
+	}
+
+	fieldsParts, extensionParts := splitExtensionFields(reFieldsMatcher.FindAllString(expression, -1))
+	if len(extensionParts) > 0 {
+		return "", fmt.Errorf("the extension fields are not supported in Quartz expressions")
+	}
+
+	switch len(fieldsParts) {
+	case 5:
+		fieldsParts = append(append([]string{"0"}, fieldsParts...), "*")
+	case 6:
+		fieldsParts = append(fieldsParts, "*")
+	case 8:
+		return "", fmt.Errorf("the milliseconds field is not supported in Quartz expressions")
+	}
+
+	if err := checkQuartzFields(fieldsParts); err != nil {
+		return "", err
+	}
+
+	// The days are calculated from both fields, so that a `*` in
+	// one of them means every day regardless of the other field.
+	dom, dow := fieldsParts[3], fieldsParts[5]
+
+	switch {
+	case dom == "?" || dow == "?":
+	case dom == "*" || dow == "*":
+		dom, dow = "*", "?"
+	default:
+		return "", fmt.Errorf("the DoM and DoW fields cannot be restricted at the same time in Quartz expressions")
+	}
+
+	dow, err = mapDoWField(dow, func(v int) (int, bool) {
+		return v%7 + 1, v >= 0 && v <= 7
+	})
+	if err != nil {
+		return "", err
+	}
+
+	fieldsParts[3], fieldsParts[5] = dom, dow
+
+	return strings.Join(fieldsParts, " "), nil
+}
+
+/* ==================================================================================================== */
+
+// checkQuartzFields rejects the special characters which are not supported by Quartz.
+func checkQuartzFields(fieldsParts []string) error {
+	for _, part := range fieldsParts {
+		for _, expr := range strings.Split(strings.ToUpper(part), ",") {
+			switch {
+			case expr == "R" || strings.HasSuffix(expr, "/R"):
+				return fmt.Errorf("the special character 'R' is not supported in Quartz expressions")
+			case expr == ".":
+				return fmt.Errorf("the special character '.' is not supported in Quartz expressions")
+			}
+		}
+	}
+
+	return nil
+}
+
+// mapDoWField maps all numeric values of the given DoW field by the given function.
+// The names of the days, the steps and the `#` values are not mapped.
+func mapDoWField(field string, fn func(int) (int, bool)) (string, error) {
+	parts := strings.Split(field, ",")
+
+	mapValue := func(value string) (string, error) {
+		v, err := strconv.Atoi(value)
+		if err != nil {
+			return value, nil // A name like `MON`.
+		}
+
+		mapped, ok := fn(v)
+		if !ok {
+			_, err := errValue(value, typeDoW)
+
+			return "", err
+		}
+
+		return strconv.Itoa(mapped), nil
+	}
+
+	for i, part := range parts {
+		switch {
+		case part == "?" || strings.EqualFold(part, "L") || reDoWWildcardPart.MatchString(part):
+			continue
+		case reDoWSpecialPart.MatchString(part):
+			matches := reDoWSpecialPart.FindStringSubmatch(part)
+
+			v, err := mapValue(matches[1])
+			if err != nil {
+				return "", err
+			}
+
+			parts[i] = v + matches[2]
+		case reDoWPart.MatchString(part):
+			matches := reDoWPart.FindStringSubmatch(part)
+
+			v1, err := mapValue(matches[1])
+			if err != nil {
+				return "", err
+			}
+
+			parts[i] = v1
+
+			if matches[3] != "" {
+				v2, err := mapValue(matches[3])
+				if err != nil {
+					return "", err
+				}
+
+				parts[i] += "-" + v2
+			}
+
+			parts[i] += matches[4]
+		}
+	}
+
+	return strings.Join(parts, ","), nil
+}
@@ -0,0 +1,114 @@
+package cron
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestQuartz_FromQuartz(t *testing.T) {
+	type testCase struct {
+		expr string
+		exp  string
+		err  string
+	}
+
+	for _, tc := range []testCase{
+		{"0 0 12 ? * MON-FRI", "0 0 12 ? * MON-FRI *", ``},
+		{"0 15 10 15 * ? 2025", "0 15 10 15 * ? 2025", ``},
+		{"0 0 9 ? * 2#1", "0 0 9 ? * 1#1 *", ``},
+		{"0 0 9 ? * 6L", "0 0 9 ? * 5L *", ``},
+		{"0 0 9 ? * 1,7", "0 0 9 ? * 0,6 *", ``},
+		{"0 0 9 ? * 2-6/2", "0 0 9 ? * 1-5/2 *", ``},
+		{"0 0 9 ? * 7-2", "0 0 9 ? * 6-1 *", ``},
+		{"0 0 9 ? * */2", "0 0 9 ? * */2 *", ``},
+		{"0 0 9 ? * L", "0 0 9 ? * L *", ``},
+		{"0 0 9 ? * 0", "", `invalid value in field 'day-of-week' given: '0'`},
+		{"0 0 9 * * MON", "", `the special character '?' is required in either the DoM or the DoW field of Quartz expressions`},
+		{"0 0 9 ? * ?", "", `the special character '?' is required in either the DoM or the DoW field of Quartz expressions`},
+		{"0 0 R ? * MON", "", `the special character 'R' is not supported in Quartz expressions`},
+		{"0 0 . ? * MON", "", `the special character '.' is not supported in Quartz expressions`},
+		{"0 9 ? * MON", "", `invalid Quartz expression given '0 9 ? * MON'`},
+		{"@daily", "", `invalid Quartz expression given '@daily'`},
+	} {
+		got, err := FromQuartz(tc.expr)
+		if got != tc.exp {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.exp, got)
+		}
+
+		if tc.err != "" || err != nil {
+			if eerr := fmt.Sprintf("%s", err); tc.err != eerr {
+				t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.err, eerr)
+			}
+		}
+	}
+}
+
+func TestQuartz_ToQuartz(t *testing.T) {
+	type testCase struct {
+		expr string
+		exp  string
+		err  string
+	}
+
+	for _, tc := range []testCase{
+		{"0 12 * * ?", "0 0 12 * * ? *", ``},
+		{"0 0 12 ? * MON-FRI", "0 0 12 ? * MON-FRI *", ``},
+		{"0 0 9 ? * 1#1 2025", "0 0 9 ? * 2#1 2025", ``},
+		{"0 0 9 ? * 0,6,7", "0 0 9 ? * 1,7,1 *", ``},
+		{"0 0 9 15 * * *", "0 0 9 * * ? *", ``},
+		{"@weekly", "0 0 0 * * ? *", ``},
+		{"0 0 9 15 * MON *", "", `the DoM and DoW fields cannot be restricted at the same time in Quartz expressions`},
+		{"@reboot", "", `the macro '@reboot' is not supported in Quartz expressions`},
+		{"0 0 * * * | 0 30 * * *", "", `composite expressions are not supported in Quartz expressions`},
+		{"0 0 0 0 * * ? *", "", `the milliseconds field is not supported in Quartz expressions`},
+		{"0 0 R * * ? *", "", `the special character 'R' is not supported in Quartz expressions`},
+		{"0 0 0 * * ? * doy=1", "", `the extension fields are not supported in Quartz expressions`},
+		{"X", "", `invalid expression given 'X'`},
+	} {
+		got, err := ToQuartz(tc.expr)
+		if got != tc.exp {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.exp, got)
+		}
+
+		if tc.err != "" || err != nil {
+			if eerr := fmt.Sprintf("%s", err); tc.err != eerr {
+				t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.err, eerr)
+			}
+		}
+	}
+}
+
+func TestQuartz_WithDialect(t *testing.T) {
+	s, err := Parse("0 0 9 ? * 2#1", WithDialect(DialectQuartz))
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+
+	// The first Monday of January 2023.
+	got := s.Next(testScheduleTime)
+	if exp := time.Date(2023, 1, 2, 9, 0, 0, 0, startupTime.Location()); got.String() != exp.String() {
+		t.Errorf("expected '%s', got '%s'", exp, got)
+	}
+
+	_, err = Parse("0 0 9 * * 2", WithDialect(DialectQuartz))
+	if eerr := fmt.Sprintf("%s", err); eerr != `the special character '?' is required in either the DoM or the DoW field of Quartz expressions` {
+		t.Errorf("unexpected error: %s", eerr)
+	}
+}
+
+func TestDialect_String(t *testing.T) {
+	for d, exp := range map[Dialect]string{
+		DialectNative:      "native",
+		DialectQuartz:      "quartz",
+		DialectVixie:       "vixie",
+		DialectSystemd:     "systemd",
+		DialectEventBridge: "eventbridge",
+		DialectRRule:       "rrule",
+		-1:                 "unknown",
+	} {
+		if d.String() != exp {
+			t.Errorf("expected '%s', got '%s'", exp, d.String())
+		}
+	}
+}
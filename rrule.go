@@ -0,0 +1,1038 @@
+// Copyright 2022 Alex Schneider. All rights reserved.
+
+package cron
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	freqSecondly frequency = iota
+	freqMinutely
+	freqHourly
+	freqDaily
+	freqWeekly
+	freqMonthly
+	freqYearly
+)
+
+// frequency is the `FREQ` of a recurrence rule.
+type frequency int
+
+var rruleFrequencies = map[string]frequency{
+	"SECONDLY": freqSecondly,
+	"MINUTELY": freqMinutely,
+	"HOURLY":   freqHourly,
+	"DAILY":    freqDaily,
+	"WEEKLY":   freqWeekly,
+	"MONTHLY":  freqMonthly,
+	"YEARLY":   freqYearly,
+}
+
+var rruleWeekdays = []string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+var reRRuleWeekday = regexp.MustCompile(`^([+-]?\d{1,2})?(SU|MO|TU|WE|TH|FR|SA)$`)
+
+// rruleWeekday is a value of the `BYDAY` part like `MO`, `2MO` or `-1FR`.
+type rruleWeekday struct {
+	weekday time.Weekday
+	nth     int // 0 means every weekday of the period.
+}
+
+// rrule represents a parsed recurrence rule of the RFC 5545 (iCalendar).
+type rrule struct {
+	freq       frequency
+	interval   int
+	count      int
+	until      time.Time
+	dtstart    time.Time
+	hasStart   bool // Whether the DTSTART is given explicitly.
+	bySecond   []int
+	byMinute   []int
+	byHour     []int
+	byMonthDay []int
+	byMonth    []int
+	byDay      []rruleWeekday
+	bySetPos   []int
+	wkst       time.Weekday
+	exdates    []time.Time
+	calendar   Calendar
+	maxYear    int
+	position   *rrulePosition
+}
+
+// rrulePositions is the number of the periods between the cached positions of a rule.
+const rrulePositions = 1 << 16
+
+// rrulePosition caches the numbers of the occurrences before every <cron.rrulePositions>-th
+// period and before the last counted period of a rule with `COUNT`, so that the searches don't
+// have to count the occurrences from the first period on.
+type rrulePosition struct {
+	mu        sync.Mutex
+	counts    []int
+	last      int
+	lastCount int
+}
+
+/* ==================================================================================================== */
+
+// parseRRule parses the given recurrence rule like `FREQ=MONTHLY;BYDAY=-1FR;BYHOUR=17`.
+// The rule can be preceded by the `RRULE:` name and accompanied by the `DTSTART` and
+// `EXDATE` properties, which are separated by whitespaces or line breaks. The DTSTART
+// defaults to the start of the day of the reference time, see <cron.WithReferenceTime>,
+// so that the time units which are not given by the rule are 0. The times without a
+// timezone are in the location of <cron.WithLocation> or in the local time.
+func parseRRule(expression string, o *options) (*rrule, error) {
+	r := &rrule{interval: 1, wkst: time.Monday, calendar: o.calendar, maxYear: o.maxYear, position: &rrulePosition{}}
+
+	var rule, until string
+	var exdates []string
+
+	location := time.Local
+	if o.location != nil {
+		location = o.location
+	}
+	exLocation := map[int]*time.Location{}
+
+	for _, line := range strings.Fields(expression) {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			name, value = "RRULE", line
+		}
+
+		params := strings.Split(name, ";")
+
+		var loc *time.Location
+
+		for _, param := range params[1:] {
+			if key, v, _ := strings.Cut(param, "="); strings.EqualFold(key, "TZID") {
+				l, err := time.LoadLocation(v)
+				if err != nil {
+					return nil, fmt.Errorf("invalid TZID in RRULE given '%s'", v)
+				}
+
+				loc = l
+			}
+		}
+
+		switch strings.ToUpper(params[0]) {
+		case "DTSTART":
+			if loc != nil {
+				location = loc
+			}
+
+			t, err := parseRRuleTime(value, location)
+			if err != nil {
+				return nil, err
+			}
+
+			r.dtstart, r.hasStart, location = t, true, t.Location()
+		case "RRULE":
+			if rule != "" {
+				return nil, fmt.Errorf("only one RRULE is supported, given '%s'", expression)
+			}
+
+			rule = value
+		case "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				exLocation[len(exdates)] = loc
+				exdates = append(exdates, v)
+			}
+		default:
+			return nil, fmt.Errorf("unsupported RRULE property given '%s'", params[0])
+		}
+	}
+
+	if rule == "" {
+		return nil, fmt.Errorf("invalid RRULE given '%s'", expression)
+	}
+
+	if !r.hasStart {
+		y, m, d := o.now.In(location).Date()
+		r.dtstart = time.Date(y, m, d, 0, 0, 0, 0, location)
+	}
+
+	var hasFreq bool
+
+	for _, part := range strings.Split(strings.ToUpper(rule), ";") {
+		key, value, _ := strings.Cut(part, "=")
+
+		if key == "UNTIL" {
+			until = value
+		} else if err := r.setPart(key, value); err != nil {
+			return nil, err
+		}
+
+		hasFreq = hasFreq || key == "FREQ"
+	}
+
+	if !hasFreq {
+		return nil, fmt.Errorf("the RRULE part 'FREQ' is required, given '%s'", rule)
+	} else if r.count > 0 && until != "" {
+		return nil, fmt.Errorf("the RRULE parts 'COUNT' and 'UNTIL' cannot be combined, given '%s'", rule)
+	} else if err := r.check(); err != nil {
+		return nil, err
+	}
+
+	if until != "" {
+		t, err := parseRRuleTime(until, location)
+		if err != nil {
+			return nil, err
+		}
+
+		r.until = t
+	}
+
+	for i, v := range exdates {
+		loc := location
+		if exLocation[i] != nil {
+			loc = exLocation[i]
+		}
+
+		t, err := parseRRuleTime(v, loc)
+		if err != nil {
+			return nil, err
+		}
+
+		r.exdates = append(r.exdates, t)
+	}
+
+	return r, nil
+}
+
+func (r *rrule) setPart(key, value string) error {
+	var err error
+
+	switch key {
+	case "FREQ":
+		freq, ok := rruleFrequencies[value]
+		if !ok {
+			return errRRuleValue(key, value)
+		}
+
+		r.freq = freq
+	case "INTERVAL":
+		r.interval, err = strconv.Atoi(value)
+		if err != nil || r.interval < 1 {
+			return errRRuleValue(key, value)
+		}
+	case "COUNT":
+		r.count, err = strconv.Atoi(value)
+		if err != nil || r.count < 1 {
+			return errRRuleValue(key, value)
+		}
+	case "BYSECOND":
+		r.bySecond, err = parseRRuleValues(key, value, 0, 59, false)
+	case "BYMINUTE":
+		r.byMinute, err = parseRRuleValues(key, value, 0, 59, false)
+	case "BYHOUR":
+		r.byHour, err = parseRRuleValues(key, value, 0, 23, false)
+	case "BYMONTHDAY":
+		r.byMonthDay, err = parseRRuleValues(key, value, 1, 31, true)
+	case "BYMONTH":
+		r.byMonth, err = parseRRuleValues(key, value, 1, 12, false)
+	case "BYSETPOS":
+		r.bySetPos, err = parseRRuleValues(key, value, 1, 366, true)
+	case "BYDAY":
+		for _, v := range strings.Split(value, ",") {
+			matches := reRRuleWeekday.FindStringSubmatch(v)
+			if len(matches) != 3 {
+				return errRRuleValue(key, value)
+			}
+
+			wd := rruleWeekday{weekday: time.Weekday(indexOf(rruleWeekdays, matches[2]))}
+
+			if matches[1] != "" {
+				wd.nth, _ = strconv.Atoi(matches[1])
+				if wd.nth == 0 || wd.nth < -53 || wd.nth > 53 {
+					return errRRuleValue(key, value)
+				}
+			}
+
+			r.byDay = append(r.byDay, wd)
+		}
+	case "WKST":
+		i := indexOf(rruleWeekdays, value)
+		if i < 0 {
+			return errRRuleValue(key, value)
+		}
+
+		r.wkst = time.Weekday(i)
+	case "BYWEEKNO", "BYYEARDAY":
+		return fmt.Errorf("the RRULE part '%s' is not supported", key)
+	default:
+		return fmt.Errorf("invalid RRULE part given '%s'", key)
+	}
+
+	return err
+}
+
+// check validates the combination of the parts of the rule.
+func (r *rrule) check() error {
+	if r.freq == freqWeekly && len(r.byMonthDay) > 0 {
+		return fmt.Errorf("the RRULE part 'BYMONTHDAY' is not allowed with 'FREQ=WEEKLY'")
+	}
+
+	for _, wd := range r.byDay {
+		if wd.nth != 0 && r.freq != freqMonthly && r.freq != freqYearly {
+			return fmt.Errorf("the numeric values of the RRULE part 'BYDAY' are only allowed with 'FREQ=MONTHLY' or 'FREQ=YEARLY'")
+		} else if wd.nth != 0 && r.freq == freqMonthly && (wd.nth < -5 || wd.nth > 5) {
+			return errRRuleValue("BYDAY", strconv.Itoa(wd.nth)+rruleWeekdays[wd.weekday])
+		}
+	}
+
+	return nil
+}
+
+func parseRRuleValues(key, value string, min, max int, negative bool) ([]int, error) {
+	var values []int
+
+	for _, v := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(v)
+		if err != nil || (n < 0 && !negative) {
+			return nil, errRRuleValue(key, value)
+		}
+
+		// The negative values count from the end of the period.
+		abs := n
+		if abs < 0 {
+			abs = -abs
+		}
+
+		if abs < min || abs > max {
+			return nil, errRRuleValue(key, value)
+		}
+
+		values = append(values, n)
+	}
+
+	values = uniqueValues(values)
+
+	sort.Ints(values)
+
+	return values, nil
+}
+
+// parseRRuleTime parses the date-time formats of the RFC 5545 like `20230101T090000Z`,
+// `20230101T090000` (in the given location) or `20230101`.
+func parseRRuleTime(value string, location *time.Location) (time.Time, error) {
+	var t time.Time
+	var err error
+
+	switch {
+	case strings.HasSuffix(value, "Z"):
+		t, err = time.Parse("20060102T150405Z", value)
+	case strings.Contains(value, "T"):
+		t, err = time.ParseInLocation("20060102T150405", value, location)
+	default:
+		t, err = time.ParseInLocation("20060102", value, location)
+	}
+
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date-time in RRULE given '%s'", value)
+	}
+
+	return t, nil
+}
+
+func errRRuleValue(key, value string) error {
+	return fmt.Errorf("invalid value in RRULE part '%s' given: '%s'", key, value)
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+
+	return -1
+}
+
+/* ==================================================================================================== */
+
+func (r *rrule) next(referenceTime time.Time) (time.Time, State) {
+	if referenceTime.IsZero() {
+		return time.Time{}, StateZeroTime
+	}
+
+	var k, n int
+
+	if r.count == 0 {
+		// Without COUNT, all periods before the reference time can be skipped.
+		if k = r.periodIndex(referenceTime.In(r.dtstart.Location())) - 1; k < 0 {
+			k = 0
+		}
+	} else {
+		// With COUNT, the occurrences are counted from the last cached period before the reference time.
+		k, n = r.position.get(r.periodIndex(referenceTime.In(r.dtstart.Location())))
+	}
+
+	// The search is limited by the periods without counted occurrences, the COUNT limits the others.
+	for i := 0; i < maxCompositeIterations; k++ {
+		start := r.period(k)
+		if (!r.until.IsZero() && start.After(r.until)) || start.Year() > r.maxYear {
+			break
+		}
+
+		// The periods, which are ruled out by the BY parts of the coarser units, are skipped at once.
+		if skip := r.firstPeriod(r.skip(start)); skip > k {
+			k, i = skip-1, i+1
+
+			continue
+		}
+
+		if r.count > 0 {
+			r.position.set(k, n)
+		}
+
+		counted := n
+
+		for _, t := range r.occurrences(start) {
+			if t.Before(r.dtstart) {
+				continue
+			} else if !r.until.IsZero() && t.After(r.until) {
+				return time.Time{}, StateNoMatches
+			}
+
+			if n++; r.count > 0 && n > r.count {
+				return time.Time{}, StateNoMatches
+			}
+
+			if t.After(referenceTime) && !r.isExcluded(t) && !isExcluded(r.calendar, t) {
+				return t, StateFound
+			}
+		}
+
+		if r.count == 0 || n == counted {
+			i++
+		}
+	}
+
+	return time.Time{}, StateNoMatches
+}
+
+// skip returns the start of the next coarser unit, if the BY parts of the unit rule out the
+// period starting at the given time, e.g. the next month of `FREQ=SECONDLY;BYMONTH=1` in
+// February. Otherwise, the given time is returned.
+func (r *rrule) skip(start time.Time) time.Time {
+	y, m, d := start.Date()
+	h, min, _ := start.Clock()
+	loc := start.Location()
+
+	switch {
+	case r.freq > freqDaily:
+		return start
+	case len(r.byMonth) > 0 && !containsValue(r.byMonth, int(m)):
+		return time.Date(y, m+1, 1, 0, 0, 0, 0, loc)
+	case r.freq == freqDaily:
+		return start
+	case (len(r.byMonthDay) > 0 || len(r.byDay) > 0) && len(r.days(start)) == 0:
+		return time.Date(y, m, d+1, 0, 0, 0, 0, loc)
+	case r.freq < freqHourly && len(r.byHour) > 0 && !containsValue(r.byHour, h):
+		return time.Date(y, m, d, h+1, 0, 0, 0, loc)
+	case r.freq < freqMinutely && len(r.byMinute) > 0 && !containsValue(r.byMinute, min):
+		return time.Date(y, m, d, h, min+1, 0, 0, loc)
+	}
+
+	return start
+}
+
+// firstPeriod returns the index of the first period, which starts at or after the given time.
+func (r *rrule) firstPeriod(t time.Time) int {
+	k := r.periodIndex(t)
+	if r.period(k).Before(t) {
+		k++
+	}
+
+	return k
+}
+
+// get returns the last cached period, which is not after the given period, and the number of
+// the occurrences before it.
+func (p *rrulePosition) get(period int) (int, int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	i := period / rrulePositions
+	if i >= len(p.counts) {
+		i = len(p.counts) - 1
+	}
+
+	if p.last <= period && p.last >= i*rrulePositions {
+		return p.last, p.lastCount
+	} else if i < 0 {
+		return 0, 0
+	}
+
+	return i * rrulePositions, p.counts[i]
+}
+
+// set caches the number of the occurrences before the given period for all positions up to it,
+// which are not cached yet. The periods are counted continuously from a cached position, so that
+// the skipped periods between the positions don't have any occurrences.
+func (p *rrulePosition) set(period, count int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for len(p.counts)*rrulePositions <= period {
+		p.counts = append(p.counts, count)
+	}
+
+	p.last, p.lastCount = period, count
+}
+
+func (r *rrule) isExcluded(t time.Time) bool {
+	for _, ex := range r.exdates {
+		if ex.Equal(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// period returns the start of the k-th period of the rule.
+func (r *rrule) period(k int) time.Time {
+	d, n := r.dtstart, k*r.interval
+
+	switch r.freq {
+	case freqYearly:
+		return time.Date(d.Year()+n, 1, 1, 0, 0, 0, 0, d.Location())
+	case freqMonthly:
+		return time.Date(d.Year(), d.Month()+time.Month(n), 1, 0, 0, 0, 0, d.Location())
+	case freqWeekly:
+		offset := (int(d.Weekday()) - int(r.wkst) + 7) % 7
+
+		return time.Date(d.Year(), d.Month(), d.Day()-offset+7*n, 0, 0, 0, 0, d.Location())
+	case freqDaily:
+		return time.Date(d.Year(), d.Month(), d.Day()+n, 0, 0, 0, 0, d.Location())
+	case freqHourly:
+		return time.Date(d.Year(), d.Month(), d.Day(), d.Hour(), 0, 0, 0, d.Location()).Add(time.Duration(n) * time.Hour)
+	case freqMinutely:
+		return time.Date(d.Year(), d.Month(), d.Day(), d.Hour(), d.Minute(), 0, 0, d.Location()).Add(time.Duration(n) * time.Minute)
+	}
+
+	return d.Add(time.Duration(n) * time.Second)
+}
+
+// periodIndex returns the index of the period containing the given time.
+func (r *rrule) periodIndex(t time.Time) int {
+	d := r.dtstart
+
+	switch r.freq {
+	case freqYearly:
+		return (t.Year() - d.Year()) / r.interval
+	case freqMonthly:
+		return ((t.Year()-d.Year())*12 + int(t.Month()) - int(d.Month())) / r.interval
+	case freqWeekly:
+		return daysBetween(r.period(0), t) / 7 / r.interval
+	case freqDaily:
+		return daysBetween(d, t) / r.interval
+	}
+
+	unit := time.Second
+
+	switch r.freq {
+	case freqHourly:
+		unit = time.Hour
+	case freqMinutely:
+		unit = time.Minute
+	}
+
+	return int(t.Sub(r.period(0)) / (unit * time.Duration(r.interval)))
+}
+
+// occurrences returns all sorted occurrences of the period starting at the given time.
+func (r *rrule) occurrences(start time.Time) []time.Time {
+	hours := r.values(freqHourly, r.byHour, start.Hour(), r.dtstart.Hour())
+	minutes := r.values(freqMinutely, r.byMinute, start.Minute(), r.dtstart.Minute())
+	seconds := r.values(freqSecondly, r.bySecond, start.Second(), r.dtstart.Second())
+
+	var occurrences []time.Time
+
+	for _, day := range r.days(start) {
+		for _, h := range hours {
+			for _, m := range minutes {
+				for _, s := range seconds {
+					occurrences = append(occurrences, time.Date(day.Year(), day.Month(), day.Day(), h, m, s, 0, start.Location()))
+				}
+			}
+		}
+	}
+
+	sort.Slice(occurrences, func(i, j int) bool {
+		return occurrences[i].Before(occurrences[j])
+	})
+
+	if len(r.bySetPos) == 0 {
+		return occurrences
+	}
+
+	var selected []time.Time
+
+	for i, t := range occurrences {
+		for _, pos := range r.bySetPos {
+			if pos == i+1 || pos == i-len(occurrences) {
+				selected = append(selected, t)
+
+				break
+			}
+		}
+	}
+
+	return selected
+}
+
+// values returns the values of the given time unit. If the frequency is not coarser than the
+// unit, the value of the period is filtered by the given BY values. Otherwise, the BY values
+// or the value of the DTSTART are returned.
+func (r *rrule) values(unit frequency, by []int, periodValue, startValue int) []int {
+	if r.freq <= unit {
+		if len(by) > 0 && !containsValue(by, periodValue) {
+			return nil
+		}
+
+		return []int{periodValue}
+	} else if len(by) > 0 {
+		return by
+	}
+
+	return []int{startValue}
+}
+
+// days returns the days of the period starting at the given time as UTC dates.
+func (r *rrule) days(start time.Time) []time.Time {
+	var first, last time.Time
+
+	date := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch r.freq {
+	case freqYearly:
+		first, last = date, date.AddDate(1, 0, -1)
+	case freqMonthly:
+		first, last = date, date.AddDate(0, 1, -1)
+	case freqWeekly:
+		first, last = date, date.AddDate(0, 0, 6)
+	default:
+		first, last = date, date
+	}
+
+	// Without any BY part of the days, the days are derived from the DTSTART.
+	noDays := len(r.byMonthDay) == 0 && len(r.byDay) == 0
+
+	var days []time.Time
+
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		switch {
+		case len(r.byMonth) > 0 && !containsValue(r.byMonth, int(d.Month())):
+			continue
+		case r.freq == freqYearly && len(r.byMonth) == 0 && noDays && d.Month() != r.dtstart.Month():
+			continue
+		case r.freq >= freqMonthly && noDays && d.Day() != r.dtstart.Day():
+			continue
+		case r.freq == freqWeekly && noDays && d.Weekday() != r.dtstart.Weekday():
+			continue
+		case !r.matchesMonthDay(d) || !r.matchesDay(d):
+			continue
+		}
+
+		days = append(days, d)
+	}
+
+	return days
+}
+
+func (r *rrule) matchesMonthDay(d time.Time) bool {
+	if len(r.byMonthDay) == 0 {
+		return true
+	}
+
+	lastDay := d.AddDate(0, 1, -d.Day()).Day()
+
+	for _, v := range r.byMonthDay {
+		if v == d.Day() || lastDay+v+1 == d.Day() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *rrule) matchesDay(d time.Time) bool {
+	if len(r.byDay) == 0 {
+		return true
+	}
+
+	// The numeric values refer to the month, or to the year for `FREQ=YEARLY` without `BYMONTH`.
+	first := d.AddDate(0, 0, 1-d.Day())
+	last := first.AddDate(0, 1, -1)
+
+	if r.freq == freqYearly && len(r.byMonth) == 0 {
+		first = d.AddDate(0, 0, 1-d.YearDay())
+		last = first.AddDate(1, 0, -1)
+	}
+
+	for _, wd := range r.byDay {
+		switch {
+		case wd.weekday != d.Weekday():
+			continue
+		case wd.nth == 0,
+			wd.nth > 0 && daysBetween(first, d)/7+1 == wd.nth,
+			wd.nth < 0 && daysBetween(d, last)/7+1 == -wd.nth:
+			return true
+		}
+	}
+
+	return false
+}
+
+// daysBetween returns the number of calendar days from a to b.
+func daysBetween(a, b time.Time) int {
+	ua := time.Date(a.Year(), a.Month(), a.Day(), 0, 0, 0, 0, time.UTC)
+	ub := time.Date(b.Year(), b.Month(), b.Day(), 0, 0, 0, 0, time.UTC)
+
+	return int(ub.Sub(ua).Hours() / 24)
+}
+
+/* ==================================================================================================== */
+
+// FromRRule converts the given recurrence rule to the 7 fields syntax of this package where possible.
+// The parts `COUNT`, `UNTIL` and `BYSETPOS`, the `EXDATE` property and the intervals which are not
+// divisors of the range of the unit are not supported. The start and the timezone of the DTSTART are
+// not preserved, only its values are used for the time units which are not given by the rule.
+func FromRRule(rule string) (string, error) {
+	r, err := parseRRule(rule, newOptions(nil))
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case r.count > 0 || !r.until.IsZero():
+		return "", fmt.Errorf("the RRULE parts 'COUNT' and 'UNTIL' are not supported in cron expressions")
+	case len(r.bySetPos) > 0:
+		return "", fmt.Errorf("the RRULE part 'BYSETPOS' is not supported in cron expressions")
+	case len(r.exdates) > 0:
+		return "", fmt.Errorf("the RRULE property 'EXDATE' is not supported in cron expressions")
+	case len(r.byMonthDay) > 0 && len(r.byDay) > 0:
+		return "", fmt.Errorf("the RRULE parts 'BYMONTHDAY' and 'BYDAY' cannot be combined in cron expressions")
+	case r.interval > 1 && (r.freq == freqDaily || r.freq == freqWeekly):
+		return "", fmt.Errorf("the RRULE part 'INTERVAL' is not supported with 'FREQ=DAILY' or 'FREQ=WEEKLY' in cron expressions")
+	}
+
+	noDays := len(r.byMonthDay) == 0 && len(r.byDay) == 0
+
+	// Without DTSTART, the time units which are not given by the rule start at 0.
+	start := r.dtstart
+	if !r.hasStart {
+		if (noDays && r.freq >= freqWeekly) || (r.interval > 1 && r.freq >= freqMonthly) {
+			return "", fmt.Errorf("the RRULE property 'DTSTART' is required to convert the rule '%s'", rule)
+		}
+
+		start = time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	}
+
+	fieldsParts := make([]string, 7)
+
+	for i, unit := range []struct {
+		freq  frequency
+		by    []int
+		start int
+		size  int
+	}{
+		{freqSecondly, r.bySecond, start.Second(), 60},
+		{freqMinutely, r.byMinute, start.Minute(), 60},
+		{freqHourly, r.byHour, start.Hour(), 24},
+	} {
+		if fieldsParts[i], err = r.cronField(unit.freq, unit.by, unit.start, 0, unit.size); err != nil {
+			return "", err
+		}
+	}
+
+	// month
+	if r.freq == freqYearly && len(r.byMonth) == 0 && !noDays {
+		fieldsParts[4] = "*"
+	} else if fieldsParts[4], err = r.cronField(freqMonthly, r.byMonth, int(start.Month()), 1, 12); err != nil {
+		return "", err
+	}
+
+	// DoM and DoW
+	fieldsParts[3], fieldsParts[5] = "*", "*"
+
+	switch {
+	case len(r.byMonthDay) > 0:
+		fieldsParts[5] = "?"
+		fieldsParts[3], err = fromRRuleMonthDays(r.byMonthDay)
+	case len(r.byDay) > 0:
+		fieldsParts[3] = "?"
+		fieldsParts[5], err = fromRRuleWeekdays(r.byDay, r.freq == freqYearly && len(r.byMonth) == 0)
+	case r.freq == freqWeekly:
+		fieldsParts[3], fieldsParts[5] = "?", strconv.Itoa(int(start.Weekday()))
+	case r.freq >= freqMonthly:
+		fieldsParts[3], fieldsParts[5] = strconv.Itoa(start.Day()), "?"
+	}
+
+	if err != nil {
+		return "", err
+	}
+
+	// year
+	fieldsParts[6] = "*"
+	if r.freq == freqYearly && r.interval > 1 {
+		fieldsParts[6] = fmt.Sprintf("%d/%d", start.Year(), r.interval)
+	}
+
+	expression := strings.Join(fieldsParts, " ")
+
+	if _, err := getFields(expression, newOptions(nil)); err != nil {
+		return "", err
+	}
+
+	return expression, nil
+}
+
+// cronField returns the field of the given time unit. The interval of the frequency
+// of the unit is only supported if it is a divisor of the size of the unit.
+func (r *rrule) cronField(unit frequency, by []int, start, min, size int) (string, error) {
+	switch {
+	case r.freq == unit && r.interval > 1:
+		if size%r.interval != 0 || len(by) > 0 {
+			return "", fmt.Errorf("the RRULE part 'INTERVAL' with the value '%d' is not supported in cron expressions", r.interval)
+		}
+
+		return fmt.Sprintf("%d/%d", min+(start-min)%r.interval, r.interval), nil
+	case len(by) > 0:
+		return joinValues(by), nil
+	case r.freq <= unit:
+		return "*", nil
+	}
+
+	return strconv.Itoa(start), nil
+}
+
+func fromRRuleMonthDays(byMonthDay []int) (string, error) {
+	var parts []string
+
+	for _, v := range byMonthDay {
+		switch {
+		case v == -1:
+			parts = append(parts, "L")
+		case v < 0:
+			return "", fmt.Errorf("the RRULE part 'BYMONTHDAY' with the value '%d' is not supported in cron expressions", v)
+		default:
+			parts = append(parts, strconv.Itoa(v))
+		}
+	}
+
+	return strings.Join(parts, ","), nil
+}
+
+func fromRRuleWeekdays(byDay []rruleWeekday, inYear bool) (string, error) {
+	var parts []string
+
+	for _, wd := range byDay {
+		switch {
+		case wd.nth == 0:
+			parts = append(parts, strconv.Itoa(int(wd.weekday)))
+		case wd.nth == -1 && !inYear:
+			parts = append(parts, fmt.Sprintf("%dL", wd.weekday))
+		case wd.nth > 0 && wd.nth <= 5 && !inYear:
+			parts = append(parts, fmt.Sprintf("%d#%d", wd.weekday, wd.nth))
+		default:
+			return "", fmt.Errorf("the RRULE part 'BYDAY' with the value '%d%s' is not supported in cron expressions", wd.nth, rruleWeekdays[wd.weekday])
+		}
+	}
+
+	return strings.Join(parts, ","), nil
+}
+
+// ToRRule converts the given expression of this package to a recurrence rule where possible.
+// The expression must not restrict both, the DoM and the DoW fields, and must not contain the
+// special characters `W` and `LW`, the milliseconds, the year or the extension fields.
+func ToRRule(expression string) (string, error) {
+	expression = strings.TrimSpace(expression)
+
+	e, err := expressionFromMacro(expression)
+	if err != nil {
+		return "", err
+	} else if e == "~" {
+		return "", fmt.Errorf("the macro '@reboot' is not supported in RRULE")
+	} else if e != "" {
+		expression = e
+	}
+
+	if isCompositeExpression(expression) {
+		return "", fmt.Errorf("composite expressions are not supported in RRULE")
+	}
+
+	fs, err := getFields(expression, newOptions(nil))
+	if err != nil {
+		return "", err
+	}
+
+	switch year := fs.year.combinations; {
+	case fs.week != nil || fs.doy != nil:
+		return "", fmt.Errorf("the extension fields are not supported in RRULE")
+	case len(fs.millis.combinations[0].values) != 1 || fs.millis.combinations[0].values[0] != 0:
+		return "", fmt.Errorf("the milliseconds field is not supported in RRULE")
+	case len(year) != 1 || year[0].unit != "/" || year[0].values[0] != DefaultMinYear || year[0].values[1] != 1:
+		return "", fmt.Errorf("the year field is not supported in RRULE")
+	}
+
+	byMonthDay, domRestricted, err := toRRuleMonthDays(fs.dom)
+	if err != nil {
+		return "", err
+	}
+
+	byDay, dowRestricted, hasNth := toRRuleWeekdays(fs.dow)
+
+	// The days are calculated from both fields, so that a `*` in one of them means every day.
+	switch {
+	case domRestricted && dowRestricted:
+		return "", fmt.Errorf("the DoM and DoW fields cannot be restricted at the same time in RRULE")
+	case byMonthDay == "*" || byDay == "*":
+		byMonthDay, byDay = "", ""
+	}
+
+	// The frequency is the finest unit which is not restricted.
+	seconds, minutes, hours, months := fs.seconds, fs.minutes, fs.hours, fs.month
+
+	freq := freqYearly
+
+	switch {
+	case isFullField(seconds, 60):
+		freq = freqSecondly
+	case isFullField(minutes, 60):
+		freq = freqMinutely
+	case isFullField(hours, 24):
+		freq = freqHourly
+	case byMonthDay == "" && !hasNth:
+		freq = freqDaily
+	case isFullField(months, 12):
+		freq = freqMonthly
+	}
+
+	// The numeric values of `BYDAY` are only allowed with `FREQ=MONTHLY` or `FREQ=YEARLY`.
+	if hasNth && freq < freqMonthly {
+		freq = freqMonthly
+		if !isFullField(months, 12) {
+			freq = freqYearly
+		}
+	}
+
+	parts := []string{"FREQ=" + indexFrequency(freq)}
+
+	if !isFullField(months, 12) {
+		parts = append(parts, "BYMONTH="+joinValues(months.combinations[0].values))
+	}
+
+	if byMonthDay != "" {
+		parts = append(parts, "BYMONTHDAY="+byMonthDay)
+	}
+
+	if byDay != "" {
+		parts = append(parts, "BYDAY="+byDay)
+	}
+
+	for _, unit := range []struct {
+		name  string
+		freq  frequency
+		field *field
+		size  int
+	}{
+		{"BYHOUR", freqHourly, hours, 24},
+		{"BYMINUTE", freqMinutely, minutes, 60},
+		{"BYSECOND", freqSecondly, seconds, 60},
+	} {
+		if freq > unit.freq || !isFullField(unit.field, unit.size) {
+			parts = append(parts, unit.name+"="+joinValues(unit.field.combinations[0].values))
+		}
+	}
+
+	return strings.Join(parts, ";"), nil
+}
+
+func toRRuleMonthDays(dom *field) (string, bool, error) {
+	var parts []string
+
+	if isFullField(dom, 31) {
+		return "*", false, nil
+	}
+
+	for _, combi := range dom.combinations {
+		switch combi.unit {
+		case "?":
+			return "", false, nil
+		case "L":
+			parts = append(parts, "-1")
+		case "":
+			parts = append(parts, joinValues(combi.values))
+		default:
+			return "", false, fmt.Errorf("the special characters 'W' and 'LW' are not supported in RRULE")
+		}
+	}
+
+	return strings.Join(parts, ","), true, nil
+}
+
+func toRRuleWeekdays(dow *field) (string, bool, bool) {
+	var parts []string
+	var hasNth bool
+
+	if isFullField(dow, 7) {
+		return "*", false, false
+	}
+
+	for _, combi := range dow.combinations {
+		switch combi.unit {
+		case "?":
+			return "", false, false
+		case "L":
+			parts, hasNth = append(parts, "-1"+rruleWeekdays[combi.values[0]%7]), true
+		case "#":
+			parts, hasNth = append(parts, strconv.Itoa(combi.values[1])+rruleWeekdays[combi.values[0]%7]), true
+		default:
+			var values []int
+
+			for _, v := range combi.values {
+				values = append(values, v%7)
+			}
+
+			for _, v := range uniqueValues(values) {
+				parts = append(parts, rruleWeekdays[v])
+			}
+		}
+	}
+
+	return strings.Join(parts, ","), true, hasNth
+}
+
+func isFullField(f *field, size int) bool {
+	return len(f.combinations) == 1 && f.combinations[0].unit == "" && len(f.combinations[0].values) == size
+}
+
+func indexFrequency(freq frequency) string {
+	for name, f := range rruleFrequencies {
+		if f == freq {
+			return name
+		}
+	}
+
+	return ""
+}
+
+func joinValues(values []int) string {
+	parts := make([]string, len(values))
+
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+
+	return strings.Join(parts, ",")
+}
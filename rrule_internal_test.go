@@ -0,0 +1,289 @@
+package cron
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRRule_parseRRule(t *testing.T) {
+	type testCase struct {
+		expr string
+		err  string
+	}
+
+	for _, tc := range []testCase{
+		{"FREQ=MONTHLY;BYDAY=-1FR;BYHOUR=17", ``},
+		{"RRULE:FREQ=DAILY;COUNT=10", ``},
+		{"DTSTART;TZID=Europe/Berlin:20230101T090000\nRRULE:FREQ=WEEKLY;WKST=SU;BYDAY=MO,FR", ``},
+		{"DTSTART:20230101 RRULE:FREQ=YEARLY;UNTIL=20300101 EXDATE:20240101,20250101", ``},
+		{"", `invalid RRULE given ''`},
+		{"BYHOUR=17", `the RRULE part 'FREQ' is required, given 'BYHOUR=17'`},
+		{"FREQ=DAILY;COUNT=1;UNTIL=20300101", `the RRULE parts 'COUNT' and 'UNTIL' cannot be combined, given 'FREQ=DAILY;COUNT=1;UNTIL=20300101'`},
+		{"FREQ=DAILY RRULE:FREQ=HOURLY", `only one RRULE is supported, given 'FREQ=DAILY RRULE:FREQ=HOURLY'`},
+		{"FREQ=FORTNIGHTLY", `invalid value in RRULE part 'FREQ' given: 'FORTNIGHTLY'`},
+		{"FREQ=DAILY;INTERVAL=0", `invalid value in RRULE part 'INTERVAL' given: '0'`},
+		{"FREQ=DAILY;COUNT=X", `invalid value in RRULE part 'COUNT' given: 'X'`},
+		{"FREQ=DAILY;BYHOUR=24", `invalid value in RRULE part 'BYHOUR' given: '24'`},
+		{"FREQ=DAILY;BYMINUTE=-1", `invalid value in RRULE part 'BYMINUTE' given: '-1'`},
+		{"FREQ=MONTHLY;BYMONTHDAY=0", `invalid value in RRULE part 'BYMONTHDAY' given: '0'`},
+		{"FREQ=MONTHLY;BYMONTHDAY=-32", `invalid value in RRULE part 'BYMONTHDAY' given: '-32'`},
+		{"FREQ=MONTHLY;BYDAY=XX", `invalid value in RRULE part 'BYDAY' given: 'XX'`},
+		{"FREQ=MONTHLY;BYDAY=6MO", `invalid value in RRULE part 'BYDAY' given: '6MO'`},
+		{"FREQ=MONTHLY;WKST=XX", `invalid value in RRULE part 'WKST' given: 'XX'`},
+		{"FREQ=DAILY;BYDAY=1MO", `the numeric values of the RRULE part 'BYDAY' are only allowed with 'FREQ=MONTHLY' or 'FREQ=YEARLY'`},
+		{"FREQ=WEEKLY;BYMONTHDAY=1", `the RRULE part 'BYMONTHDAY' is not allowed with 'FREQ=WEEKLY'`},
+		{"FREQ=YEARLY;BYWEEKNO=1", `the RRULE part 'BYWEEKNO' is not supported`},
+		{"FREQ=YEARLY;FOO=1", `invalid RRULE part given 'FOO'`},
+		{"RDATE:20230101 FREQ=DAILY", `unsupported RRULE property given 'RDATE'`},
+		{"DTSTART:2023 FREQ=DAILY", `invalid date-time in RRULE given '2023'`},
+		{"DTSTART;TZID=Mars/Base:20230101T090000 FREQ=DAILY", `invalid TZID in RRULE given 'Mars/Base'`},
+	} {
+		_, err := parseRRule(tc.expr, newOptions(nil))
+
+		if tc.err != "" || err != nil {
+			if eerr := fmt.Sprintf("%s", err); tc.err != eerr {
+				t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.err, eerr)
+			}
+		}
+	}
+}
+
+func TestRRule_next(t *testing.T) {
+	type testCase struct {
+		expr  string
+		ref   time.Time
+		exp   time.Time
+		state State
+	}
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+
+	for _, tc := range []testCase{
+		// The last Friday of the month at 17:00.
+		{"DTSTART:20230101T090000Z RRULE:FREQ=MONTHLY;BYDAY=-1FR;BYHOUR=17", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 27, 17, 0, 0, 0, time.UTC), StateFound},
+		{"DTSTART:20230101T090000Z RRULE:FREQ=MONTHLY;BYDAY=-1FR;BYHOUR=17", time.Date(2023, 1, 27, 17, 0, 0, 0, time.UTC), time.Date(2023, 2, 24, 17, 0, 0, 0, time.UTC), StateFound},
+		// COUNT
+		{"DTSTART:20230101T090000Z RRULE:FREQ=DAILY;COUNT=3", time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 1, 9, 0, 0, 0, time.UTC), StateFound},
+		{"DTSTART:20230101T090000Z RRULE:FREQ=DAILY;COUNT=3", time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC), time.Date(2023, 1, 3, 9, 0, 0, 0, time.UTC), StateFound},
+		{"DTSTART:20230101T090000Z RRULE:FREQ=DAILY;COUNT=3", time.Date(2023, 1, 3, 9, 0, 0, 0, time.UTC), time.Time{}, StateNoMatches},
+		// UNTIL
+		{"DTSTART:20230101T090000Z RRULE:FREQ=WEEKLY;UNTIL=20230115T090000Z", time.Date(2023, 1, 8, 9, 0, 0, 0, time.UTC), time.Date(2023, 1, 15, 9, 0, 0, 0, time.UTC), StateFound},
+		{"DTSTART:20230101T090000Z RRULE:FREQ=WEEKLY;UNTIL=20230115T090000Z", time.Date(2023, 1, 15, 9, 0, 0, 0, time.UTC), time.Time{}, StateNoMatches},
+		// INTERVAL
+		{"DTSTART:20230101T090000Z RRULE:FREQ=DAILY;INTERVAL=10", time.Date(2023, 1, 5, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 11, 9, 0, 0, 0, time.UTC), StateFound},
+		{"DTSTART:20230101T000000Z RRULE:FREQ=HOURLY;INTERVAL=5", time.Date(2023, 3, 1, 1, 0, 0, 0, time.UTC), time.Date(2023, 3, 1, 4, 0, 0, 0, time.UTC), StateFound},
+		// BYSETPOS (last weekday of the month)
+		{"DTSTART:20230101T090000Z RRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 31, 9, 0, 0, 0, time.UTC), StateFound},
+		// EXDATE
+		{"DTSTART:20230101T090000Z RRULE:FREQ=DAILY EXDATE:20230102T090000Z", time.Date(2023, 1, 1, 9, 0, 0, 0, time.UTC), time.Date(2023, 1, 3, 9, 0, 0, 0, time.UTC), StateFound},
+		// TZID
+		{"DTSTART;TZID=Europe/Berlin:20230101T090000 RRULE:FREQ=DAILY", time.Date(2023, 1, 1, 8, 30, 0, 0, time.UTC), time.Date(2023, 1, 2, 9, 0, 0, 0, berlin), StateFound},
+		// The fourth Thursday of November.
+		{"DTSTART:20230101T120000Z RRULE:FREQ=YEARLY;BYMONTH=11;BYDAY=4TH", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 11, 23, 12, 0, 0, 0, time.UTC), StateFound},
+		// The first Monday of the year.
+		{"DTSTART:20230101T120000Z RRULE:FREQ=YEARLY;BYDAY=1MO", time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC), StateFound},
+		// The day of the DTSTART.
+		{"DTSTART:20230315T083000Z RRULE:FREQ=YEARLY", time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 15, 8, 30, 0, 0, time.UTC), StateFound},
+		{"DTSTART:20230131T083000Z RRULE:FREQ=MONTHLY", time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 3, 31, 8, 30, 0, 0, time.UTC), StateFound},
+		// The periods ruled out by the coarser units.
+		{"DTSTART:20230101T000000Z RRULE:FREQ=SECONDLY;BYMONTH=1", time.Date(2023, 2, 5, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), StateFound},
+		{"DTSTART:20230101T000000Z RRULE:FREQ=SECONDLY;BYDAY=MO;BYHOUR=9;BYMINUTE=30", time.Date(2023, 1, 3, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 9, 9, 30, 0, 0, time.UTC), StateFound},
+		{"DTSTART:20230101T000000Z RRULE:FREQ=MINUTELY;INTERVAL=11;BYMONTHDAY=15", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 15, 0, 3, 0, 0, time.UTC), StateFound},
+		{"DTSTART:20230101T000000Z RRULE:FREQ=HOURLY;BYMONTH=2;BYMONTHDAY=30;UNTIL=20500101T000000Z", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), time.Time{}, StateNoMatches},
+		{"FREQ=DAILY", time.Time{}, time.Time{}, StateZeroTime},
+	} {
+		r, err := parseRRule(tc.expr, newOptions(nil))
+		if err != nil {
+			t.Fatalf("'%s': unexpected error: %#v", tc.expr, err)
+		}
+
+		got, state := r.next(tc.ref)
+		if got.String() != tc.exp.String() {
+			t.Errorf("'%s' (%s): expected '%s', got '%s'", tc.expr, tc.ref, tc.exp, got)
+		}
+
+		if state != tc.state {
+			t.Errorf("'%s' (%s): expected '%s', got '%s'", tc.expr, tc.ref, tc.state, state)
+		}
+	}
+}
+
+func TestRRule_next_Count(t *testing.T) {
+	start := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	r, err := parseRRule("DTSTART:20230101T000000Z RRULE:FREQ=SECONDLY;COUNT=1100000", newOptions(nil))
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+
+	// The occurrences are counted beyond the limit of the search and from the cached positions.
+	for _, seconds := range []int{1050000, 1050001, 5, 1099998, 70000, 1060000} {
+		ref := start.Add(time.Duration(seconds) * time.Second)
+
+		got, state := r.next(ref)
+		if exp := ref.Add(time.Second); got.String() != exp.String() || state != StateFound {
+			t.Errorf("'%s': expected '%s', got '%s' (%s)", ref, exp, got, state)
+		}
+	}
+
+	ref := start.Add(1099999 * time.Second)
+	if got, state := r.next(ref); state != StateNoMatches {
+		t.Errorf("'%s': expected '%s', got '%s' (%s)", ref, StateNoMatches, got, state)
+	}
+}
+
+func TestRRule_FromRRule(t *testing.T) {
+	type testCase struct {
+		expr string
+		exp  string
+		err  string
+	}
+
+	for _, tc := range []testCase{
+		{"FREQ=MONTHLY;BYDAY=-1FR;BYHOUR=17", "0 0 17 ? * 5L *", ``},
+		{"FREQ=MONTHLY;BYDAY=2MO,4MO;BYHOUR=9;BYMINUTE=30", "0 30 9 ? * 1#2,1#4 *", ``},
+		{"FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR;BYHOUR=9;BYMINUTE=0;BYSECOND=0", "0 0 9 ? * 1,2,3,4,5 *", ``},
+		{"FREQ=MINUTELY;INTERVAL=15", "0 0/15 * * * * *", ``},
+		{"FREQ=MONTHLY;BYMONTHDAY=1,-1", "0 0 0 L,1 * ? *", ``},
+		{"FREQ=YEARLY;BYMONTH=1,7;BYMONTHDAY=1", "0 0 0 1 1,7 ? *", ``},
+		{"DTSTART:20230315T083000Z RRULE:FREQ=YEARLY", "0 30 8 15 3 ? *", ``},
+		{"DTSTART:20230315T083000Z RRULE:FREQ=YEARLY;INTERVAL=2", "0 30 8 15 3 ? 2023/2", ``},
+		{"DTSTART:20230315T083000Z RRULE:FREQ=MONTHLY;INTERVAL=3", "0 30 8 15 3/3 ? *", ``},
+		{"DTSTART:20230102T083000Z RRULE:FREQ=WEEKLY", "0 30 8 ? * 1 *", ``},
+		{"FREQ=DAILY;COUNT=10", "", `the RRULE parts 'COUNT' and 'UNTIL' are not supported in cron expressions`},
+		{"FREQ=MONTHLY;BYDAY=MO;BYSETPOS=1", "", `the RRULE part 'BYSETPOS' is not supported in cron expressions`},
+		{"FREQ=DAILY EXDATE:20230101", "", `the RRULE property 'EXDATE' is not supported in cron expressions`},
+		{"FREQ=MONTHLY;BYMONTHDAY=13;BYDAY=FR", "", `the RRULE parts 'BYMONTHDAY' and 'BYDAY' cannot be combined in cron expressions`},
+		{"FREQ=DAILY;INTERVAL=2", "", `the RRULE part 'INTERVAL' is not supported with 'FREQ=DAILY' or 'FREQ=WEEKLY' in cron expressions`},
+		{"FREQ=HOURLY;INTERVAL=5", "", `the RRULE part 'INTERVAL' with the value '5' is not supported in cron expressions`},
+		{"FREQ=WEEKLY", "", `the RRULE property 'DTSTART' is required to convert the rule 'FREQ=WEEKLY'`},
+		{"FREQ=MONTHLY;BYMONTHDAY=-2", "", `the RRULE part 'BYMONTHDAY' with the value '-2' is not supported in cron expressions`},
+		{"FREQ=YEARLY;BYDAY=20MO", "", `the RRULE part 'BYDAY' with the value '20MO' is not supported in cron expressions`},
+		{"FREQ=X", "", `invalid value in RRULE part 'FREQ' given: 'X'`},
+	} {
+		got, err := FromRRule(tc.expr)
+		if got != tc.exp {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.exp, got)
+		}
+
+		if tc.err != "" || err != nil {
+			if eerr := fmt.Sprintf("%s", err); tc.err != eerr {
+				t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.err, eerr)
+			}
+		}
+	}
+}
+
+func TestRRule_ToRRule(t *testing.T) {
+	type testCase struct {
+		expr string
+		exp  string
+		err  string
+	}
+
+	for _, tc := range []testCase{
+		{"0 0 17 ? * 5L *", "FREQ=MONTHLY;BYDAY=-1FR;BYHOUR=17;BYMINUTE=0;BYSECOND=0", ``},
+		{"0 0 9 ? * MON-FRI *", "FREQ=DAILY;BYDAY=MO,TU,WE,TH,FR;BYHOUR=9;BYMINUTE=0;BYSECOND=0", ``},
+		{"0 0 9 * * MON *", "FREQ=DAILY;BYHOUR=9;BYMINUTE=0;BYSECOND=0", ``},
+		{"0 0 0 1,L * ? *", "FREQ=MONTHLY;BYMONTHDAY=-1,1;BYHOUR=0;BYMINUTE=0;BYSECOND=0", ``},
+		{"0 0 0 ? 11 4#4 *", "FREQ=YEARLY;BYMONTH=11;BYDAY=4TH;BYHOUR=0;BYMINUTE=0;BYSECOND=0", ``},
+		{"0 */15 * * * * *", "FREQ=HOURLY;BYMINUTE=0,15,30,45;BYSECOND=0", ``},
+		{"* * * * * * *", "FREQ=SECONDLY", ``},
+		{"0 0 0 1 1 ? *", "FREQ=YEARLY;BYMONTH=1;BYMONTHDAY=1;BYHOUR=0;BYMINUTE=0;BYSECOND=0", ``},
+		{"@hourly", "FREQ=HOURLY;BYMINUTE=0;BYSECOND=0", ``},
+		{"@reboot", "", `the macro '@reboot' is not supported in RRULE`},
+		{"0 0 * * * | 0 30 * * *", "", `composite expressions are not supported in RRULE`},
+		{"0 0 0 15 * MON *", "", `the DoM and DoW fields cannot be restricted at the same time in RRULE`},
+		{"0 0 0 15W * ? *", "", `the special characters 'W' and 'LW' are not supported in RRULE`},
+		{"0 0 0 * * * 2025", "", `the year field is not supported in RRULE`},
+		{"500 0 0 0 * * * *", "", `the milliseconds field is not supported in RRULE`},
+		{"0 0 0 * * * * doy=1", "", `the extension fields are not supported in RRULE`},
+		{"X", "", `invalid expression given 'X'`},
+	} {
+		got, err := ToRRule(tc.expr)
+		if got != tc.exp {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.exp, got)
+		}
+
+		if tc.err != "" || err != nil {
+			if eerr := fmt.Sprintf("%s", err); tc.err != eerr {
+				t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.err, eerr)
+			}
+		}
+	}
+}
+
+func TestRRule_WithDialect(t *testing.T) {
+	now := time.Date(2023, 6, 1, 0, 0, 1, 0, time.UTC)
+
+	// The converted rules are executed at the same times as the expressions.
+	for _, expr := range []string{
+		"0 0 17 ? * 5L *",
+		"0 0 9 ? * MON-FRI *",
+		"0 */15 * * * * *",
+		"0 0 0 1,L * ? *",
+		"0 0 0 ? 11 4#4 *",
+	} {
+		rule, err := ToRRule(expr)
+		if err != nil {
+			t.Fatalf("'%s': unexpected error: %#v", expr, err)
+		}
+
+		s1, err := Parse(expr)
+		if err != nil {
+			t.Fatalf("'%s': unexpected error: %#v", expr, err)
+		}
+
+		s2, err := Parse("DTSTART:20230601T000000Z RRULE:"+rule, WithDialect(DialectRRule))
+		if err != nil {
+			t.Fatalf("'%s': unexpected error: %#v", rule, err)
+		}
+
+		next1, next2 := now, now
+
+		for i := 0; i < 5; i++ {
+			next1, next2 = s1.Next(next1), s2.Next(next2)
+
+			if next1.String() != next2.String() {
+				t.Errorf("'%s': expected '%s', got '%s'", rule, next1, next2)
+			}
+		}
+	}
+}
+
+func TestRRule_WithOptions(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	ref := time.Date(2023, 1, 2, 10, 46, 22, 0, berlin)
+	holidays := NewDateCalendar(time.Date(2023, 1, 27, 0, 0, 0, 0, berlin))
+
+	type testCase struct {
+		rule string
+		opts []Option
+		exp  time.Time
+	}
+
+	for _, tc := range []testCase{
+		// The time units, which are not given by the rule, are 0 without DTSTART.
+		{"FREQ=MONTHLY;BYDAY=-1FR;BYHOUR=17", []Option{WithReferenceTime(ref)}, time.Date(2023, 1, 27, 17, 0, 0, 0, time.Local)},
+		{"FREQ=MONTHLY;BYDAY=-1FR;BYHOUR=17", []Option{WithReferenceTime(ref), WithLocation(berlin)}, time.Date(2023, 1, 27, 17, 0, 0, 0, berlin)},
+		{"FREQ=MONTHLY;BYDAY=-1FR;BYHOUR=17", []Option{WithReferenceTime(ref), WithLocation(berlin), WithCalendar(holidays)}, time.Date(2023, 2, 24, 17, 0, 0, 0, berlin)},
+		{"FREQ=YEARLY;BYMONTH=1;BYMONTHDAY=1", []Option{WithReferenceTime(ref), WithYearRange(1970, 2023)}, time.Time{}},
+	} {
+		s, err := Parse(tc.rule, append(tc.opts, WithDialect(DialectRRule))...)
+		if err != nil {
+			t.Fatalf("'%s': unexpected error: %#v", tc.rule, err)
+		}
+
+		if got := s.Next(ref); !got.Equal(tc.exp) {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.rule, tc.exp, got)
+		}
+	}
+}
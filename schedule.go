@@ -5,44 +5,143 @@ package cron
 import (
 	"context"
 	"fmt"
-	"sort"
 	"strings"
 	"time"
 )
 
 // Job communicates job-related information to the recipient.
 type Job struct {
-	// Next contains the time for the next cronjob execution
-	// or time.Zero if cronjob has finished.
+	// Next contains the time of the execution following the current one, like
+	// <cron.Job.Upcoming>, which it is kept for. Use <cron.Job.Scheduled> for the time
+	// of the execution, which just became due.
 	Next time.Time
 
+	// Upcoming contains the time of the execution following the current one, the time of
+	// the first execution for <cron.StateScheduled> or time.Zero if the cronjob has finished.
+	Upcoming time.Time
+
 	// State contains the current cronjob state.
-	State int
+	State State
+
+	// Scheduled contains the time, at which the current execution was scheduled.
+	Scheduled time.Time
+
+	// Dispatched contains the time, at which the current execution was actually
+	// dispatched to the channel.
+	Dispatched time.Time
+
+	// Lateness contains the delay of the dispatching after the scheduled time.
+	Lateness time.Duration
+
+	// Seq contains the sequence number of the current execution starting at 1, it is 0 for
+	// the events without an execution, e.g. <cron.StateScheduled>.
+	Seq uint64
+
+	// Skipped contains the number of the scheduled executions, which were skipped
+	// since the previous execution, e.g. because the system was suspended.
+	Skipped int
+
+	// Expression contains the expression of the schedule given to <cron.NewJobCh>.
+	Expression string
+
+	// Name contains the name of the schedule given by <cron.WithName>.
+	Name string
+}
+
+// Schedule represents a parsed cron expression.
+type Schedule struct {
+	expression string
+	spec       spec
+	native     bool // Whether the expression uses the native syntax, see <cron.Schedule.MarshalText>.
 }
 
 // schedule represents the <cron.schedule> object.
 type schedule struct {
-	ctx    context.Context
-	fields *fields
-	jobCh  chan *Job
+	ctx      context.Context
+	fields   *fields
+	spec     spec
+	calendar Calendar
+	jobCh    chan *Job
+	offset   int    // Position of the expression within a composite expression.
+	source   string // Expression given to <cron.NewJobCh>.
+	name     string
+	legacy   bool // Whether the former events are sent, see <cron.WithLegacyJobs>.
+}
+
+/* ==================================================================================================== */
+
+// Parse parses the given expression spec and returns a new <cron.Schedule>.
+func Parse(expression string, opts ...Option) (*Schedule, error) {
+	o := newOptions(opts)
+
+	sp, err := parseSpec(expression, o)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Schedule{expression: strings.TrimSpace(expression), spec: sp, native: o.dialect == DialectNative}, nil
+}
+
+// Next returns the time of the next execution of the schedule, that is greater than
+// the given reference time. A zero time value is returned if there is no next execution.
+func (s *Schedule) Next(referenceTime time.Time) time.Time {
+	if s.IsZero() {
+		return time.Time{}
+	}
+
+	next, _ := s.spec.next(referenceTime)
+
+	return next
+}
+
+// Matches reports whether the schedule is executed at the given time, which is truncated to
+// milliseconds. The result is the same as comparing the given time with the next execution
+// after the previous millisecond, but the fields are evaluated directly.
+func (s *Schedule) Matches(t time.Time) bool {
+	if s.IsZero() {
+		return false
+	}
+
+	return matchesSpec(s.spec, t.Truncate(resolution))
+}
+
+// String implements the <fmt.Stringer> interface.
+func (s *Schedule) String() string {
+	if s == nil {
+		return ""
+	}
+
+	return s.expression
+}
+
+// IsZero reports whether the schedule is nil or the zero value, which is never executed.
+func (s *Schedule) IsZero() bool {
+	return s == nil || s.spec == nil
 }
 
 /* ==================================================================================================== */
 
 // NewJobCh parses the given expression spec and
 // returns a new read only communication channel.
-func NewJobCh(ctx context.Context, expression string) (<-chan *Job, error) {
-	fields, err := getFields(expression)
+func NewJobCh(ctx context.Context, expression string, opts ...Option) (<-chan *Job, error) {
+	o := newOptions(opts)
+
+	sp, err := parseSpec(expression, o)
 	if err != nil {
 		return nil, err
 	}
 
-	s := &schedule{
-		ctx:    ctx,
-		fields: fields,
-		jobCh:  make(chan *Job),
+	s, ok := sp.(*schedule)
+	if !ok {
+		s = &schedule{spec: sp}
 	}
 
+	s.ctx = ctx
+	s.jobCh = make(chan *Job)
+	s.source = strings.TrimSpace(expression)
+	s.name = o.name
+	s.legacy = o.legacyJobs
+
 	// To be able to override in tests.
 	nowFn := func() time.Time {
 		return time.Now()
@@ -64,16 +163,107 @@ func NewJobCh(ctx context.Context, expression string) (<-chan *Job, error) {
 //   - no new time can be found for the next execution.
 //
 // The second return value notifies the caller about the accured case.
-func (s *schedule) next(referenceTime time.Time) (time.Time, state) {
+func (s *schedule) next(referenceTime time.Time) (time.Time, State) {
 	if referenceTime.IsZero() {
 		return time.Time{}, StateZeroTime
+	} else if s.spec != nil {
+		return s.spec.next(referenceTime)
 	} else if s.fields.once {
 		return time.Time{}, StateOnceExec
 	}
 
-	referenceTime = referenceTime.Add(time.Duration(1) * time.Second)
+	if s.fields.location != nil {
+		referenceTime = referenceTime.In(s.fields.location)
+	}
+
+	referenceTime = referenceTime.Truncate(resolution).Add(resolution)
 
-	return s.fromNextBestYear(referenceTime)
+	return s.search(referenceTime)
+}
+
+// matches reports whether the wall clock of the given time in the location of the expression
+// matches all fields. Both times of a wall clock, that is repeated by the daylight saving time
+// changes, are matched like by the <cron.schedule.next> method.
+func (s *schedule) matches(t time.Time) bool {
+	fs := s.fields
+
+	if t.IsZero() || fs.once || t.Nanosecond()%int(resolution) != 0 {
+		return false
+	} else if fs.location != nil {
+		t = t.In(fs.location)
+	}
+
+	year, month, day := t.Date()
+	hour, minute, second := t.Clock()
+
+	if y, ok := fs.nextYear(year); !ok || y != year {
+		return false
+	} else if !fs.month.bits.has(int(month)) || !fs.hours.bits.has(hour) || !fs.minutes.bits.has(minute) {
+		return false
+	} else if !fs.seconds.bits.has(second) || !fs.millis.bits.has(t.Nanosecond()/int(resolution)) {
+		return false
+	}
+
+	return s.getDays(time.Date(year, month, 1, 0, 0, 0, 0, t.Location()))&(1<<day) != 0
+}
+
+// matchedUntil returns the last time of the consecutive executions, which start at the given
+// matching time. The executions are consecutive up to the end of the smallest unit, whose finer
+// fields contain all values of the given other schedules, e.g. up to the end of the hour for
+// `* * 2 * * ?` and the other schedule `*/5 * * * * ?`. All values are required without other
+// schedules or if the locations differ.
+func (s *schedule) matchedUntil(t time.Time, others []*schedule) time.Time {
+	fs := s.fields
+
+	for _, other := range others {
+		if !sameLocation(fs.location, other.fields.location) {
+			others = nil
+
+			break
+		}
+	}
+
+	covered := func(get func(fs *fields) *field, max int) bool {
+		if others == nil {
+			return get(fs).bits.hasRange(0, max)
+		}
+
+		for _, other := range others {
+			if !get(fs).bits.covers(get(other.fields).bits) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	local := t
+	if fs.location != nil {
+		local = t.In(fs.location)
+	}
+
+	var end time.Time
+
+	switch {
+	case !covered(func(fs *fields) *field { return fs.millis }, 999):
+		return t
+	case !covered(func(fs *fields) *field { return fs.seconds }, 59):
+		end = t.Truncate(time.Second).Add(time.Second)
+	case !covered(func(fs *fields) *field { return fs.minutes }, 59):
+		end = t.Truncate(time.Minute).Add(time.Minute)
+	case !covered(func(fs *fields) *field { return fs.hours }, 23):
+		_, minute, second := local.Clock()
+		end = t.Truncate(time.Second).Add(-time.Duration(minute*60+second) * time.Second).Add(time.Hour)
+	default:
+		year, month, day := local.Date()
+		end = time.Date(year, month, day+1, 0, 0, 0, 0, local.Location())
+	}
+
+	if !end.After(t) {
+		return t
+	}
+
+	return end.Add(-resolution).In(t.Location())
 }
 
 func (s *schedule) run(nowFn func() time.Time) {
@@ -88,247 +278,277 @@ func (s *schedule) run(nowFn func() time.Time) {
 	}()
 
 	// @reboot case.
-	if s.fields.once {
-		s.jobCh <- &Job{
-			State: int(StateOnceExec),
-		}
+	if s.fields != nil && s.fields.once {
+		now := nowFn()
+
+		s.send(&Job{
+			State:      StateOnceExec,
+			Scheduled:  now,
+			Dispatched: now,
+			Seq:        1,
+		})
 
 		return
 	}
 
 	now := nowFn()
 	next, state := s.next(now)
-	if state == StateFound {
-		ticker = time.NewTicker(next.Sub(now))
+	if state != StateFound {
+		if !s.legacy {
+			s.send(&Job{State: StateNoMatches, Dispatched: now})
+		}
+
+		return
+	}
+
+	// The ticker runs while the scheduled job is sent, so that a late recipient doesn't delay it.
+	ticker = time.NewTicker(next.Sub(now))
+
+	if !s.legacy && !s.send(&Job{State: StateScheduled, Next: next, Upcoming: next, Dispatched: now}) {
+		return
+	}
+
+	for seq := uint64(1); ; seq++ {
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		scheduled := next
+		now = nowFn()
+
+		// The ticker may fire slightly before the scheduled time by the wall clock.
+		ref := now
+		if ref.Before(scheduled) {
+			ref = scheduled
+		}
+
+		job := &Job{
+			State:      StateFound,
+			Scheduled:  scheduled,
+			Dispatched: now,
+			Lateness:   now.Sub(scheduled),
+			Seq:        seq,
+			Skipped:    s.countSkipped(scheduled, ref),
+		}
+
+		next, state = s.next(ref)
+		if state != StateFound {
+			if s.legacy {
+				job.Next, job.State = time.Time{}, StateNoMatches
+				s.send(job)
 
-		for {
-			select {
-			case <-s.ctx.Done():
 				return
-			case now := <-ticker.C:
-				next, state = s.next(now)
-				if state != StateFound {
-					s.jobCh <- &Job{
-						State: int(StateNoMatches),
-					}
-
-					return
-				}
-
-				ticker.Stop()
-				ticker = time.NewTicker(next.Sub(now))
-
-				s.jobCh <- &Job{
-					Next:  next,
-					State: int(StateFound),
-				}
 			}
+
+			// The last execution is followed by the end of the cronjob without an execution.
+			if s.send(job) {
+				s.send(&Job{State: StateNoMatches, Dispatched: now})
+			}
+
+			return
+		}
+
+		ticker.Stop()
+		ticker = time.NewTicker(next.Sub(now))
+
+		job.Next, job.Upcoming = next, next
+
+		if !s.send(job) {
+			return
 		}
 	}
 }
 
-/* ==================================================================================================== */
+// send sends the given job to the channel unless the context is done. The identity of the
+// schedule is set on the job.
+func (s *schedule) send(job *Job) bool {
+	job.Expression = s.source
+	job.Name = s.name
+
+	select {
+	case <-s.ctx.Done():
+		return false
+	case s.jobCh <- job:
+		return true
+	}
+}
 
-func (s *schedule) fromNextBestYear(referenceTime time.Time) (time.Time, state) {
-	values := s.fields.year.combinations[0].values
+// countSkipped returns the number of the executions after the given scheduled time, which
+// are not after the given dispatch time. The executions are counted up to the bound of the
+// <cron.schedule.search> method.
+func (s *schedule) countSkipped(scheduled, dispatched time.Time) int {
+	var skipped int
 
-	i := sort.SearchInts(values, referenceTime.Year())
-	if i == len(values) {
-		return time.Time{}, StateNoMatches
-	}
+	for t := scheduled; skipped < maxSearchSteps; skipped++ {
+		next, state := s.next(t)
+		if state != StateFound || next.After(dispatched) {
+			break
+		}
 
-	if values[i] != referenceTime.Year() {
-		return s.fromNextBestYear(time.Date(
-			values[i],
-			time.Month(s.fields.month.combinations[0].values[0]),
-			1,
-			s.fields.hours.combinations[0].values[0],
-			s.fields.minutes.combinations[0].values[0],
-			s.fields.seconds.combinations[0].values[0],
-			0,
-			referenceTime.Location(),
-		))
+		t = next
 	}
 
-	return s.fromNextBestMonth(referenceTime)
+	return skipped
 }
 
-func (s *schedule) fromNextBestMonth(referenceTime time.Time) (time.Time, state) {
-	values := s.fields.month.combinations[0].values
-
-	i := sort.SearchInts(values, int(referenceTime.Month()))
-	if i == len(values) {
-		return s.fromNextBestYear(time.Date(
-			referenceTime.AddDate(1, 0, 0).Year(),
-			time.Month(s.fields.month.combinations[0].values[0]),
-			1,
-			s.fields.hours.combinations[0].values[0],
-			s.fields.minutes.combinations[0].values[0],
-			s.fields.seconds.combinations[0].values[0],
-			0,
-			referenceTime.Location(),
-		))
-	} else if values[i] != int(referenceTime.Month()) {
-		referenceTime = referenceTime.AddDate(0, values[i]-int(referenceTime.Month()), 0)
-
-		return s.fromNextBestYear(time.Date(
-			referenceTime.Year(),
-			time.Month(referenceTime.Month()),
-			1,
-			s.fields.hours.combinations[0].values[0],
-			s.fields.minutes.combinations[0].values[0],
-			s.fields.seconds.combinations[0].values[0],
-			0,
-			referenceTime.Location(),
-		))
-	}
-
-	return s.fromNextBestDay(referenceTime)
-}
+/* ==================================================================================================== */
 
-func (s *schedule) fromNextBestDay(referenceTime time.Time) (time.Time, state) {
-	values := s.getDaysValues(referenceTime)
-
-	i := sort.SearchInts(values, referenceTime.Day())
-	if i == len(values) {
-		referenceTime = referenceTime.AddDate(0, 1, 0)
-
-		return s.fromNextBestYear(time.Date(
-			referenceTime.Year(),
-			time.Month(referenceTime.Month()),
-			1,
-			s.fields.hours.combinations[0].values[0],
-			s.fields.minutes.combinations[0].values[0],
-			s.fields.seconds.combinations[0].values[0],
-			0,
-			referenceTime.Location(),
-		))
-	} else if values[i] != referenceTime.Day() {
-		referenceTime = referenceTime.AddDate(0, 0, values[i]-referenceTime.Day())
-
-		return time.Date(
-			referenceTime.Year(),
-			time.Month(referenceTime.Month()),
-			referenceTime.Day(),
-			s.fields.hours.combinations[0].values[0],
-			s.fields.minutes.combinations[0].values[0],
-			s.fields.seconds.combinations[0].values[0],
-			0,
-			referenceTime.Location(),
-		), StateFound
-	}
-
-	return s.fromNextBestHour(referenceTime)
-}
+// maxSearchSteps bounds the iterations of the <cron.schedule.search> method. Each step
+// moves at least to the next value of one of the fields, so that the bound is only reached
+// by the expressions, which match rarely or never within the year range.
+const maxSearchSteps = 1 << 16
+
+// search returns the first time of the schedule, that is greater than or equal to the given
+// reference time. The fields are matched from the year to the milliseconds. If a field has
+// no more matching value, the next coarser unit is incremented and the finer units are reset.
+func (s *schedule) search(referenceTime time.Time) (time.Time, State) {
+	fs := s.fields
+	loc := referenceTime.Location()
+
+	year, m, day := referenceTime.Date()
+	hour, minute, second := referenceTime.Clock()
+	month := int(m)
+	millis := referenceTime.Nanosecond() / int(time.Millisecond)
+
+	for step := 0; step < maxSearchSteps; step++ {
+		y, ok := fs.nextYear(year)
+		if !ok {
+			break
+		} else if y != year {
+			year, month, day, hour, minute, second, millis = y, 1, 1, 0, 0, 0, 0
+		}
 
-func (s *schedule) fromNextBestHour(referenceTime time.Time) (time.Time, state) {
-	values := s.fields.hours.combinations[0].values
-
-	i := sort.SearchInts(values, referenceTime.Hour())
-	if i == len(values) {
-		referenceTime = referenceTime.AddDate(0, 0, 1)
-
-		return s.fromNextBestYear(time.Date(
-			referenceTime.Year(),
-			time.Month(referenceTime.Month()),
-			referenceTime.Day(),
-			s.fields.hours.combinations[0].values[0],
-			s.fields.minutes.combinations[0].values[0],
-			s.fields.seconds.combinations[0].values[0],
-			0,
-			referenceTime.Location(),
-		))
-	} else if values[i] != referenceTime.Hour() {
-		referenceTime = referenceTime.Add(time.Duration(values[i]-referenceTime.Hour()) * time.Hour)
-
-		return time.Date(
-			referenceTime.Year(),
-			time.Month(referenceTime.Month()),
-			referenceTime.Day(),
-			referenceTime.Hour(),
-			s.fields.minutes.combinations[0].values[0],
-			s.fields.seconds.combinations[0].values[0],
-			0,
-			referenceTime.Location(),
-		), StateFound
-	}
-
-	return s.fromNextBestMinute(referenceTime)
-}
+		v, ok := fs.month.bits.next(month)
+		if !ok {
+			year, month, day, hour, minute, second, millis = year+1, 1, 1, 0, 0, 0, 0
+
+			continue
+		} else if v != month {
+			month, day, hour, minute, second, millis = v, 1, 0, 0, 0, 0
+		}
 
-func (s *schedule) fromNextBestMinute(referenceTime time.Time) (time.Time, state) {
-	values := s.fields.minutes.combinations[0].values
-
-	i := sort.SearchInts(values, referenceTime.Minute())
-	if i == len(values) {
-		referenceTime = referenceTime.Add(time.Duration(1) * time.Hour)
-
-		return s.fromNextBestYear(time.Date(
-			referenceTime.Year(),
-			time.Month(referenceTime.Month()),
-			referenceTime.Day(),
-			referenceTime.Hour(),
-			s.fields.minutes.combinations[0].values[0],
-			s.fields.seconds.combinations[0].values[0],
-			0,
-			referenceTime.Location(),
-		))
-	} else if values[i] != referenceTime.Minute() {
-		referenceTime = referenceTime.Add(time.Duration(values[i]-referenceTime.Minute()) * time.Minute)
-
-		return time.Date(
-			referenceTime.Year(),
-			time.Month(referenceTime.Month()),
-			referenceTime.Day(),
-			referenceTime.Hour(),
-			referenceTime.Minute(),
-			s.fields.seconds.combinations[0].values[0],
-			0,
-			referenceTime.Location(),
-		), StateFound
-	}
-
-	return s.fromNextBestSecond(referenceTime)
+		// The bitset of the days contains only the days of the current month.
+		v, ok = bitset{s.getDays(time.Date(year, time.Month(month), 1, 0, 0, 0, 0, loc))}.next(day)
+		if !ok {
+			month, day, hour, minute, second, millis = month+1, 1, 0, 0, 0, 0
+
+			if month > 12 {
+				year, month = year+1, 1
+			}
+
+			continue
+		} else if v != day {
+			day, hour, minute, second, millis = v, 0, 0, 0, 0
+		}
+
+		v, ok = fs.hours.bits.next(hour)
+		if !ok {
+			day, hour, minute, second, millis = day+1, 0, 0, 0, 0
+
+			continue
+		} else if v != hour {
+			hour, minute, second, millis = v, 0, 0, 0
+		}
+
+		v, ok = fs.minutes.bits.next(minute)
+		if !ok {
+			hour, minute, second, millis = hour+1, 0, 0, 0
+
+			continue
+		} else if v != minute {
+			minute, second, millis = v, 0, 0
+		}
+
+		v, ok = fs.seconds.bits.next(second)
+		if !ok {
+			minute, second, millis = minute+1, 0, 0
+
+			continue
+		} else if v != second {
+			second, millis = v, 0
+		}
+
+		v, ok = fs.millis.bits.next(millis)
+		if !ok {
+			second, millis = second+1, 0
+
+			continue
+		}
+
+		return toTime(referenceTime, year, month, day, hour, minute, second, v), StateFound
+	}
+
+	return time.Time{}, StateNoMatches
 }
 
-func (s *schedule) fromNextBestSecond(referenceTime time.Time) (time.Time, state) {
-	values := s.fields.seconds.combinations[0].values
+// toTime returns the time of the given wall clock in the location of the given reference time.
+// The wall clock is ambiguous if the clocks are turned back, in this case the earliest time,
+// that is not before the reference time, is returned.
+func toTime(referenceTime time.Time, year, month, day, hour, minute, second, millis int) time.Time {
+	t := time.Date(year, time.Month(month), day, hour, minute, second, millis*int(time.Millisecond), referenceTime.Location())
 
-	i := sort.SearchInts(values, referenceTime.Second())
-	if i == len(values) {
-		referenceTime = referenceTime.Add(time.Duration(1) * time.Minute)
+	_, offset := t.Zone()
+	_, refOffset := referenceTime.Zone()
+
+	if offset == refOffset {
+		return t
+	}
 
-		return s.fromNextBestYear(time.Date(
-			referenceTime.Year(),
-			time.Month(referenceTime.Month()),
-			referenceTime.Day(),
-			referenceTime.Hour(),
-			referenceTime.Minute(),
-			s.fields.seconds.combinations[0].values[0],
-			0,
-			referenceTime.Location(),
-		))
-	} else if values[i] != referenceTime.Second() {
-		referenceTime = referenceTime.Add(time.Duration(values[i]-referenceTime.Second()) * time.Second)
+	// The same wall clock in the offset of the reference time, e.g. the first 02:40 if the
+	// clocks are turned back from 03:00 to 02:00.
+	alt := t.Add(time.Duration(offset-refOffset) * time.Second)
+	if alt.Before(t) && !alt.Before(referenceTime) && alt.Hour() == hour && alt.Minute() == minute {
+		return alt
 	}
 
-	return referenceTime, StateFound
+	return t
 }
 
 /* ==================================================================================================== */
 
-func (s *schedule) getDaysValues(referenceTime time.Time) []int {
+// getDays returns the days of the month of the given reference time as a bitset word,
+// the bit n represents the day n.
+func (s *schedule) getDays(referenceTime time.Time) uint64 {
 	min := referenceTime.AddDate(0, 0, -1*referenceTime.Day()+1)
 	max := min.AddDate(0, 1, -1)
 
-	domValues := s.getDaysValuesFromDoM(min, max)
-	dowValues := s.getDaysValuesFromDoW(min, max)
+	domDays := s.getDaysFromDoM(min, max)
+	dowDays := s.getDaysFromDoW(min, max)
 
-	return uniqueValues(append(domValues, dowValues...))
+	days := domDays | dowDays
+	if s.fields.dayMode == dayModeIntersect {
+		days = domDays & dowDays
+	}
+
+	if s.fields.week == nil && s.fields.doy == nil && s.calendar == nil {
+		return days
+	}
+
+	for d, ok := (bitset{days}).next(1); ok; d, ok = (bitset{days}).next(d + 1) {
+		date := min.AddDate(0, 0, d-1)
+
+		if s.fields.week != nil {
+			if _, week := date.ISOWeek(); !s.fields.week.bits.has(week) {
+				days &^= 1 << d
+
+				continue
+			}
+		}
+
+		if s.fields.doy != nil && !s.fields.doy.bits.has(date.YearDay()) {
+			days &^= 1 << d
+		} else if isExcluded(s.calendar, date) {
+			days &^= 1 << d
+		}
+	}
+
+	return days
 }
 
-func (s *schedule) getDaysValuesFromDoM(min, max time.Time) []int {
-	var values []int
+func (s *schedule) getDaysFromDoM(min, max time.Time) uint64 {
+	var days uint64
 
 	for _, combi := range s.fields.dom.combinations {
 		if combi.unit == "?" {
@@ -337,64 +557,67 @@ func (s *schedule) getDaysValuesFromDoM(min, max time.Time) []int {
 
 		switch combi.unit {
 		case "L": // Last day of month
-			values = append(values, max.Day())
+			days |= 1 << max.Day()
 		case "LW": // Last weekday (MON-FRI) of month
-			values = append(values, getDaysValuesFromDoMLastWeekday(max))
+			days |= getDaysFromDoMLastWeekday(max, s.calendar)
 		case "W": // `15W` (nearest weekday (MON-FRI) of the month to the 15.)
-			values = append(values, getDaysValuesFromDoMWeekday(combi.values, min, max)...)
+			days |= getDaysFromDoMWeekday(combi.values[0], min, max, s.calendar)
 		default:
-			for _, d := range combi.values {
-				if d <= max.Day() {
-					values = append(values, d)
-				}
-			}
+			// The days after the last day of the month are cut off.
+			days |= s.fields.dom.bits[0] & (1<<(max.Day()+1) - 1)
 		}
 	}
 
-	return values
+	return days
 }
 
-func getDaysValuesFromDoMLastWeekday(max time.Time) int {
-	switch int(max.Weekday()) {
-	case 0:
-		return max.Day() - 2
-	case 6:
-		return max.Day() - 1
-	default:
-		return max.Day()
+func getDaysFromDoMLastWeekday(max time.Time, calendar Calendar) uint64 {
+	for curr := max; curr.Month() == max.Month(); curr = curr.AddDate(0, 0, -1) {
+		if isBusinessDay(calendar, curr) {
+			return 1 << curr.Day()
+		}
 	}
+
+	return 0
 }
 
-func getDaysValuesFromDoMWeekday(pool []int, min, max time.Time) []int {
-	var values []int
+func getDaysFromDoMWeekday(refDay int, min, max time.Time, calendar Calendar) uint64 {
+	if refDay > max.Day() {
+		return 0
+	}
 
-	refDay := pool[0]
 	curr := min.AddDate(0, 0, refDay-1)
-	wd := int(curr.Weekday())
-
-	if wd >= 1 && wd <= 5 {
-		values = append(values, curr.Day())
-	} else if wd == 0 {
-		if curr.Day() == max.Day() {
-			values = append(values, curr.AddDate(0, 0, -2).Day())
-		} else {
-			values = append(values, curr.AddDate(0, 0, 1).Day())
-		}
-	} else if wd == 6 {
-		if curr.Day() == min.Day() {
-			values = append(values, curr.AddDate(0, 0, 2).Day())
-		} else {
-			values = append(values, curr.AddDate(0, 0, -1).Day())
+	if isBusinessDay(calendar, curr) {
+		return 1 << refDay
+	}
+
+	// A Sunday moves to the following business day, all other
+	// days move to the preceding one if both are equally near.
+	directions := [2]int{-1, 1}
+	if curr.Weekday() == time.Sunday {
+		directions = [2]int{1, -1}
+	}
+
+	for distance := 1; distance < max.Day(); distance++ {
+		for _, direction := range directions {
+			day := refDay + direction*distance
+			if day < 1 || day > max.Day() {
+				continue
+			}
+
+			if isBusinessDay(calendar, min.AddDate(0, 0, day-1)) {
+				return 1 << day
+			}
 		}
 	}
 
-	return values
+	return 0
 }
 
 /* ==================================================================================================== */
 
-func (s *schedule) getDaysValuesFromDoW(min, max time.Time) []int {
-	var values []int
+func (s *schedule) getDaysFromDoW(min, max time.Time) uint64 {
+	var days uint64
 
 	for _, combi := range s.fields.dow.combinations {
 		if combi.unit == "?" {
@@ -403,75 +626,44 @@ func (s *schedule) getDaysValuesFromDoW(min, max time.Time) []int {
 
 		switch combi.unit {
 		case "L": // `5L` (last FRI of the month)
-			values = append(values, getDaysValuesFromDoWLast(combi.values, min, max)...)
+			days |= getDaysFromDoWLast(combi.values[0], max)
 		case "#": // `5#3` (nth (1-5, here 3) DoW (0-7, here FRI) of the month)
-			values = append(values, getDaysValuesFromDoWHash(combi.values, min, max)...)
+			days |= getDaysFromDoWHash(combi.values[0], combi.values[1], min, max)
 		default:
-			values = append(values, getDaysValuesFromDoWDefault(combi.values, min, max)...)
+			days |= getDaysFromDoWDefault(s.fields.dow.bits[0], min, max)
 		}
 	}
 
-	return values
+	return days
 }
 
-func getDaysValuesFromDoWLast(pool []int, min, max time.Time) []int {
-	var values []int
-
-	refDay := pool[0]
-
-	for curr := max.AddDate(0, 0, 0); curr.Day() > min.Day(); curr = curr.AddDate(0, 0, -1) {
-		if int(curr.Weekday()) == refDay {
-			values = append(values, curr.Day())
-
-			break
-		}
-	}
-
-	return values
+func getDaysFromDoWLast(weekday int, max time.Time) uint64 {
+	return 1 << (max.Day() - (int(max.Weekday())-weekday+7)%7)
 }
 
-func getDaysValuesFromDoWHash(pool []int, min, max time.Time) []int {
-	var values []int
-	var seen int
-
-	day := pool[0]
-	nth := pool[1]
-
-	for curr := min.AddDate(0, 0, 0); curr.Day() <= max.Day(); curr = curr.AddDate(0, 0, 1) {
-		if day == int(curr.Weekday()) {
-			seen++
-
-			if seen == nth {
-				values = append(values, curr.Day())
-
-				break
-			}
-		}
-
-		if curr.Day() == max.Day() {
-			break
-		}
+func getDaysFromDoWHash(weekday, nth int, min, max time.Time) uint64 {
+	day := 1 + (weekday-int(min.Weekday())+7)%7 + (nth-1)*7
+	if day > max.Day() {
+		return 0
 	}
 
-	return values
+	return 1 << day
 }
 
-func getDaysValuesFromDoWDefault(pool []int, min, max time.Time) []int {
-	var values []int
+// getDaysFromDoWDefault returns the days of the month matching the given days of the week,
+// the bit n of the given weekdays represents the day of the week n.
+func getDaysFromDoWDefault(weekdays uint64, min, max time.Time) uint64 {
+	var days uint64
 
-	for _, refDay := range pool {
-		for curr := min.AddDate(0, 0, 0); curr.Day() <= max.Day(); curr = curr.AddDate(0, 0, 1) {
-			if int(curr.Weekday()) == refDay {
-				values = append(values, curr.Day())
-			}
+	first := int(min.Weekday())
 
-			if curr.Day() == max.Day() {
-				break
-			}
+	for d := 1; d <= max.Day(); d++ {
+		if weekdays&(1<<((first+d-1)%7)) != 0 {
+			days |= 1 << d
 		}
 	}
 
-	return values
+	return days
 }
 
 /* ==================================================================================================== */
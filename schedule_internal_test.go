@@ -16,7 +16,7 @@ var testScheduleTime = time.Date(2022, 12, 31, 23, 59, 59, 0, startupTime.Locati
 func TestSchedule_Next(t *testing.T) {
 	type testCase struct {
 		expr    string
-		state   state
+		state   State
 		refTime time.Time
 		expTime time.Time
 		err     string
@@ -32,6 +32,62 @@ func TestSchedule_Next(t *testing.T) {
 			time.Date(2024, 2, 29, 0, 0, 0, 0, startupTime.Location()),
 			``,
 		},
+		{
+			"*/250 * * * * * * *",
+			StateFound,
+			time.Date(2022, 12, 31, 23, 59, 59, 600000000, startupTime.Location()),
+			time.Date(2022, 12, 31, 23, 59, 59, 750000000, startupTime.Location()),
+			``,
+		},
+		{
+			"*/250 * * * * * * *",
+			StateFound,
+			time.Date(2022, 12, 31, 23, 59, 59, 750000000, startupTime.Location()),
+			time.Date(2023, 1, 1, 0, 0, 0, 0, startupTime.Location()),
+			``,
+		},
+		{
+			"* * * * * * *",
+			StateFound,
+			time.Date(2022, 12, 31, 23, 59, 58, 500000000, startupTime.Location()),
+			time.Date(2022, 12, 31, 23, 59, 59, 0, startupTime.Location()),
+			``,
+		},
+		{
+			"0 0 0 1 1 ? *",
+			StateFound,
+			time.Date(2099, 12, 31, 23, 59, 59, 0, startupTime.Location()),
+			time.Date(2100, 1, 1, 0, 0, 0, 0, startupTime.Location()),
+			``,
+		},
+		{
+			"0 0 0 ? * MON * week=2/2",
+			StateFound,
+			testScheduleTime,
+			time.Date(2023, 1, 9, 0, 0, 0, 0, startupTime.Location()),
+			``,
+		},
+		{
+			"0 0 0 * * ? * doy=100",
+			StateFound,
+			testScheduleTime,
+			time.Date(2023, 4, 10, 0, 0, 0, 0, startupTime.Location()),
+			``,
+		},
+		{
+			"0 0 17 ? * 5L *",
+			StateFound,
+			time.Date(2026, 10, 31, 0, 0, 0, 0, startupTime.Location()),
+			time.Date(2026, 11, 27, 17, 0, 0, 0, startupTime.Location()),
+			``,
+		},
+		{
+			"0 0 0 * 2 ? *",
+			StateFound,
+			time.Date(2023, 1, 31, 12, 0, 0, 0, startupTime.Location()),
+			time.Date(2023, 2, 1, 0, 0, 0, 0, startupTime.Location()),
+			``,
+		},
 	} {
 		s, err := createTestScheduler(tc.expr)
 		if err != nil {
@@ -49,120 +105,212 @@ func TestSchedule_Next(t *testing.T) {
 	}
 }
 
-func TestSchedule_Run(t *testing.T) {
-	s, err := createTestScheduler("* * * * * * 1970")
+func TestSchedule_WithLocation(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
 	if err != nil {
-		t.Errorf("'Unexpected error: %#v", err)
+		t.Fatalf("unexpected error: %#v", err)
 	}
 
-	s.ctx = context.TODO()
-	s.jobCh = make(chan *Job)
+	s, err := Parse("0 0 9 * * ? *", WithLocation(berlin))
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
 
-	nowFn := func() time.Time {
-		return time.Date(1970, 12, 31, 23, 59, 58, 0, startupTime.Location())
+	got := s.Next(time.Date(2023, 1, 2, 8, 30, 0, 0, time.UTC))
+	if exp := time.Date(2023, 1, 3, 9, 0, 0, 0, berlin); got.String() != exp.String() {
+		t.Errorf("expected '%s', got '%s'", exp, got)
 	}
+}
 
-	go s.run(nowFn)
+func TestSchedule_Matches(t *testing.T) {
+	type testCase struct {
+		expr string
+		t    time.Time
+		exp  bool
+	}
+
+	for _, tc := range []testCase{
+		{"0 0 9 * * ? *", time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC), true},
+		{"0 0 9 * * ? *", time.Date(2023, 1, 2, 9, 0, 1, 0, time.UTC), false},
+		{"0 0 9 * * ? *", time.Date(2023, 1, 2, 9, 0, 0, 500, time.UTC), true},
+		{"0 0 9 * * ? *", time.Date(2023, 1, 2, 9, 0, 0, int(time.Millisecond), time.UTC), false},
+		{"500 0 0 9 * * ? *", time.Date(2023, 1, 2, 9, 0, 0, 500*int(time.Millisecond), time.UTC), true},
+		{"0 0 9 ? * MON *", time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC), true},
+		{"0 0 9 ? * MON *", time.Date(2023, 1, 3, 9, 0, 0, 0, time.UTC), false},
+		{"0 0 9 L * ? *", time.Date(2023, 2, 28, 9, 0, 0, 0, time.UTC), true},
+		{"0 0 9 LW * ? *", time.Date(2023, 4, 28, 9, 0, 0, 0, time.UTC), true},
+		{"0 0 9 LW * ? *", time.Date(2023, 4, 30, 9, 0, 0, 0, time.UTC), false},
+		{"0 0 9 ? * 1#1 *", time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC), true},
+		{"0 0 9 ? * 1#1 *", time.Date(2023, 1, 9, 9, 0, 0, 0, time.UTC), false},
+		{"0 0 9 * * ? 2024", time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC), false},
+		{"0 0 9 * * ? * week=1", time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC), true},
+		{"0 0 9 * * ? * | 0 0 18 * * ? *", time.Date(2023, 1, 2, 18, 0, 0, 0, time.UTC), true},
+		{"0 0 9 * * ? * & 0 0 9 ? * MON *", time.Date(2023, 1, 3, 9, 0, 0, 0, time.UTC), false},
+		{"0 0 9 * * ? * ! 0 0 9 ? * MON *", time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC), false},
+		{"0 0 9 * * ? * ! 0 0 9 ? * MON *", time.Date(2023, 1, 3, 9, 0, 0, 0, time.UTC), true},
+		{"@reboot", time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC), false},
+		{"0 0 9 * * ? *", time.Time{}, false},
+	} {
+		s, err := Parse(tc.expr)
+		if err != nil {
+			t.Fatalf("'%s': unexpected error '%s'", tc.expr, err)
+		}
 
-	var i int
+		if got := s.Matches(tc.t); got != tc.exp {
+			t.Errorf("'%s' at '%s': expected '%t', got '%t'", tc.expr, tc.t, tc.exp, got)
+		}
+	}
+}
 
-testLoop:
-	for {
-		select {
-		case job := <-s.jobCh:
-			if i == 0 && job.State != int(StateNoMatches) {
-				t.Errorf("Unexpected state: %#v", job.State)
-			}
+func TestSchedule_Matches_Next(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
 
-			i++
+	// The times around the daylight saving time changes are compared with the next executions.
+	for _, expr := range []string{
+		"0 */20 * * * ? *",
+		"0 30 2 * * ? *",
+		"0 0 9 15W * ? *",
+		"0 0 0 ? * 5L *",
+		"0 */30 1-3 * * ? * | 0 0 12 ? * SUN *",
+	} {
+		s, err := Parse(expr, WithLocation(berlin))
+		if err != nil {
+			t.Fatalf("'%s': unexpected error '%s'", expr, err)
+		}
 
-			if i >= 1 {
-				break testLoop
+		for _, start := range []time.Time{
+			time.Date(2023, 3, 25, 0, 0, 0, 0, time.UTC),
+			time.Date(2023, 10, 28, 0, 0, 0, 0, time.UTC),
+		} {
+			for tm := start; tm.Before(start.AddDate(0, 0, 2)); tm = tm.Add(10 * time.Minute) {
+				exp := matchesSpecByNext(s.spec, tm)
+				if got := s.Matches(tm); got != exp {
+					t.Errorf("'%s' at '%s': expected '%t', got '%t'", expr, tm.In(berlin), exp, got)
+				}
 			}
-		case <-s.ctx.Done():
-			t.Errorf("Unexpected 'ctx.Done()'")
 		}
 	}
 }
 
-/* ==================================================================================================== */
-
-func TestSchedule_fromNextBestYear(t *testing.T) {
+func TestSchedule_Run(t *testing.T) {
 	type testCase struct {
-		expr    string
-		state   state
-		refTime time.Time
-		expTime time.Time
+		legacy bool
+		exp    []State
 	}
 
 	for _, tc := range []testCase{
-		{"1 1 1 1 1 ? 2020-2022", StateNoMatches, testScheduleTime, time.Time{}},
-		{
-			"1 1 1 1 1 ? 2023-2024",
-			StateFound,
-			testScheduleTime,
-			time.Date(2023, 1, 1, 1, 1, 1, 0, startupTime.Location()),
-		},
+		{false, []State{StateScheduled, StateFound, StateNoMatches}},
+		{true, []State{StateNoMatches}},
 	} {
-		s, err := createTestScheduler(tc.expr)
+		s, err := createTestScheduler("* * * * * * 1970")
 		if err != nil {
-			t.Errorf("'%s': unexpected error: %#v", tc.expr, err)
+			t.Errorf("'Unexpected error: %#v", err)
 		}
 
-		tm, state := s.fromNextBestYear(tc.refTime)
-		if state != tc.state {
-			t.Errorf("'%s': expected '%d', got '%d'", tc.expr, tc.state, state)
+		ctx, cancelFn := context.WithTimeout(context.TODO(), 5*time.Second)
+
+		s.ctx = ctx
+		s.jobCh = make(chan *Job)
+		s.legacy = tc.legacy
+
+		nowFn := func() time.Time {
+			return time.Date(1970, 12, 31, 23, 59, 58, 0, startupTime.Location())
 		}
 
-		if tm.String() != tc.expTime.String() {
-			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.expTime.String(), tm.String())
+		go s.run(nowFn)
+
+		var got []State
+
+		for job := range s.jobCh {
+			got = append(got, job.State)
+
+			// The last execution has no following one.
+			if job.State == StateFound && (!job.Scheduled.Equal(time.Date(1970, 12, 31, 23, 59, 59, 0, startupTime.Location())) || !job.Next.IsZero()) {
+				t.Errorf("'%t': unexpected times '%s' and '%s'", tc.legacy, job.Scheduled, job.Next)
+			}
+
+			// The end of the cronjob is not an execution.
+			if job.State == StateNoMatches && !tc.legacy && job.Seq != 0 {
+				t.Errorf("'%t': expected '%d', got '%d'", tc.legacy, 0, job.Seq)
+			}
+		}
+
+		if fmt.Sprint(got) != fmt.Sprint(tc.exp) {
+			t.Errorf("'%t': expected '%s', got '%s'", tc.legacy, tc.exp, got)
 		}
+
+		cancelFn()
+	}
+}
+
+func TestSchedule_Run_LateRecipient(t *testing.T) {
+	s, err := createTestScheduler("* * * * * ? *")
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+
+	ctx, cancelFn := context.WithTimeout(context.TODO(), 5*time.Second)
+	defer cancelFn()
+
+	s.ctx = ctx
+	s.jobCh = make(chan *Job)
+
+	nowFn := func() time.Time {
+		return time.Date(2023, 1, 1, 0, 0, 0, 0, startupTime.Location())
+	}
+
+	go s.run(nowFn)
+
+	// The first execution is due one second after the start, while the scheduled job is not read.
+	time.Sleep(1500 * time.Millisecond)
+
+	if job := <-s.jobCh; job.State != StateScheduled {
+		t.Fatalf("expected '%s', got '%s'", StateScheduled, job.State)
+	}
+
+	start := time.Now()
+
+	if job := <-s.jobCh; job.State != StateFound {
+		t.Errorf("expected '%s', got '%s'", StateFound, job.State)
+	} else if d := time.Since(start); d > 500*time.Millisecond {
+		t.Errorf("expected the overdue execution at once, got it after '%s'", d)
 	}
 }
 
-func TestSchedule_fromNextBestMonth(t *testing.T) {
+/* ==================================================================================================== */
+
+func TestSchedule_countSkipped(t *testing.T) {
 	type testCase struct {
-		expr    string
-		state   state
-		refTime time.Time
-		expTime time.Time
+		expr       string
+		scheduled  time.Time
+		dispatched time.Time
+		exp        int
 	}
 
 	for _, tc := range []testCase{
-		{"1 1 1 1 1 ? 2020-2022", StateNoMatches, testScheduleTime, time.Time{}},
-		{
-			"1 1 1 1 1 ? 2023-2024",
-			StateFound,
-			testScheduleTime,
-			time.Date(2023, 1, 1, 1, 1, 1, 0, startupTime.Location()),
-		},
-		{
-			"1 1 1 1 12 ? 2022-2024",
-			StateFound,
-			testScheduleTime,
-			time.Date(2023, 12, 1, 1, 1, 1, 0, startupTime.Location()),
-		},
+		{"0 * * * * ? *", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 1, 0, 0, 30, 0, time.UTC), 0},
+		{"0 * * * * ? *", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 1, 0, 1, 0, 0, time.UTC), 1},
+		{"0 * * * * ? *", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2023, 1, 1, 1, 0, 30, 0, time.UTC), 60},
+		{"0 0 0 1 1 ? 2023", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 0},
 	} {
 		s, err := createTestScheduler(tc.expr)
 		if err != nil {
-			t.Errorf("'%s': unexpected error: %#v", tc.expr, err)
+			t.Fatalf("'%s': unexpected error '%s'", tc.expr, err)
 		}
 
-		tm, state := s.fromNextBestMonth(tc.refTime)
-		if state != tc.state {
-			t.Errorf("'%s': expected '%d', got '%d'", tc.expr, tc.state, state)
-		}
-
-		if tm.String() != tc.expTime.String() {
-			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.expTime.String(), tm.String())
+		if got := s.countSkipped(tc.scheduled, tc.dispatched); got != tc.exp {
+			t.Errorf("'%s': expected '%d', got '%d'", tc.expr, tc.exp, got)
 		}
 	}
 }
 
-func TestSchedule_fromNextBestDay(t *testing.T) {
+func TestSchedule_search(t *testing.T) {
 	type testCase struct {
 		expr    string
-		state   state
+		state   State
 		refTime time.Time
 		expTime time.Time
 	}
@@ -176,10 +324,10 @@ func TestSchedule_fromNextBestDay(t *testing.T) {
 			time.Date(2023, 1, 1, 1, 1, 1, 0, startupTime.Location()),
 		},
 		{
-			"1 1 1 31 1 ? 2022-2024",
+			"1 1 1 1 12 ? 2022-2024",
 			StateFound,
 			testScheduleTime,
-			time.Date(2023, 1, 31, 1, 1, 1, 0, startupTime.Location()),
+			time.Date(2023, 12, 1, 1, 1, 1, 0, startupTime.Location()),
 		},
 		{
 			"1 1 1 31 1 ? 2022-2024",
@@ -193,83 +341,48 @@ func TestSchedule_fromNextBestDay(t *testing.T) {
 			time.Date(2022, 12, 28, 23, 59, 59, 0, startupTime.Location()),
 			time.Date(2022, 12, 30, 1, 1, 1, 0, startupTime.Location()),
 		},
-	} {
-		s, err := createTestScheduler(tc.expr)
-		if err != nil {
-			t.Errorf("'%s': unexpected error: %#v", tc.expr, err)
-		}
-
-		tm, state := s.fromNextBestDay(tc.refTime)
-		if state != tc.state {
-			t.Errorf("'%s': expected '%d', got '%d'", tc.expr, tc.state, state)
-		}
-
-		if tm.String() != tc.expTime.String() {
-			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.expTime.String(), tm.String())
-		}
-	}
-}
-
-func TestSchedule_fromNextBestHour(t *testing.T) {
-	type testCase struct {
-		expr    string
-		state   state
-		refTime time.Time
-		expTime time.Time
-	}
-
-	for _, tc := range []testCase{
-		{"1 1 1 1 1 ? 2020-2022", StateNoMatches, testScheduleTime, time.Time{}},
-		{
-			"1 1 1 1 1 ? 2023-2024",
-			StateFound,
-			testScheduleTime,
-			time.Date(2023, 1, 1, 1, 1, 1, 0, startupTime.Location()),
-		},
 		{
 			"1 1 3 1,2,31 12 ? 2022",
 			StateFound,
 			time.Date(2022, 12, 15, 2, 1, 1, 0, startupTime.Location()),
-			time.Date(2022, 12, 15, 3, 1, 1, 0, startupTime.Location()),
+			time.Date(2022, 12, 31, 3, 1, 1, 0, startupTime.Location()),
 		},
-	} {
-		s, err := createTestScheduler(tc.expr)
-		if err != nil {
-			t.Errorf("'%s': unexpected error: %#v", tc.expr, err)
-		}
-
-		tm, state := s.fromNextBestHour(tc.refTime)
-		if state != tc.state {
-			t.Errorf("'%s': expected '%d', got '%d'", tc.expr, tc.state, state)
-		}
-
-		if tm.String() != tc.expTime.String() {
-			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.expTime.String(), tm.String())
-		}
-	}
-}
-
-func TestSchedule_fromNextBestMinute(t *testing.T) {
-	type testCase struct {
-		expr    string
-		state   state
-		refTime time.Time
-		expTime time.Time
-	}
-
-	for _, tc := range []testCase{
-		{"1 1 1 1 1 ? 2020-2022", StateNoMatches, testScheduleTime, time.Time{}},
 		{
-			"1 1 1 1 1 ? 2023-2024",
+			"1 5,6 1 15 12 ? 2022",
 			StateFound,
-			testScheduleTime,
-			time.Date(2023, 1, 1, 1, 1, 1, 0, startupTime.Location()),
+			time.Date(2022, 12, 15, 1, 2, 1, 0, startupTime.Location()),
+			time.Date(2022, 12, 15, 1, 5, 1, 0, startupTime.Location()),
 		},
 		{
-			"1 5,6 1 15 12 ? 2022",
+			"50 1 2 15 12 ? 2022",
 			StateFound,
 			time.Date(2022, 12, 15, 1, 2, 1, 0, startupTime.Location()),
-			time.Date(2022, 12, 15, 1, 5, 1, 0, startupTime.Location()),
+			time.Date(2022, 12, 15, 2, 1, 50, 0, startupTime.Location()),
+		},
+		{"100 1 1 1 1 1 ? 2020-2022", StateNoMatches, testScheduleTime.Add(500 * time.Millisecond), time.Time{}},
+		{
+			"100,900 50 1 2 15 12 ? 2022",
+			StateFound,
+			time.Date(2022, 12, 15, 2, 1, 50, 200000000, startupTime.Location()),
+			time.Date(2022, 12, 15, 2, 1, 50, 900000000, startupTime.Location()),
+		},
+		{
+			"0 0 0 29 2 ? *",
+			StateFound,
+			time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			"0 0 0 29 2 MON *",
+			StateFound,
+			time.Date(2023, 3, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2024, 2, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			"59 59 23 31 12 ? *",
+			StateFound,
+			time.Date(2023, 12, 31, 23, 59, 59, 1000000, time.UTC),
+			time.Date(2024, 12, 31, 23, 59, 59, 0, time.UTC),
 		},
 	} {
 		s, err := createTestScheduler(tc.expr)
@@ -277,7 +390,7 @@ func TestSchedule_fromNextBestMinute(t *testing.T) {
 			t.Errorf("'%s': unexpected error: %#v", tc.expr, err)
 		}
 
-		tm, state := s.fromNextBestMinute(tc.refTime)
+		tm, state := s.search(tc.refTime)
 		if state != tc.state {
 			t.Errorf("'%s': expected '%d', got '%d'", tc.expr, tc.state, state)
 		}
@@ -288,48 +401,63 @@ func TestSchedule_fromNextBestMinute(t *testing.T) {
 	}
 }
 
-func TestSchedule_fromNextBestSecond(t *testing.T) {
-	type testCase struct {
-		expr    string
-		state   state
-		refTime time.Time
-		expTime time.Time
+// excludeAllCalendar excludes all days.
+type excludeAllCalendar struct{}
+
+func (excludeAllCalendar) IsExcluded(time.Time) bool {
+	return true
+}
+
+func TestSchedule_search_Bounded(t *testing.T) {
+	s, err := createTestScheduler("0 0 0 * * ? *")
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
 	}
 
-	for _, tc := range []testCase{
-		{"1 1 1 1 1 ? 2020-2022", StateNoMatches, testScheduleTime, time.Time{}},
-		{
-			"1 1 1 1 1 ? 2023-2024",
-			StateFound,
-			testScheduleTime,
-			time.Date(2023, 1, 1, 1, 1, 1, 0, startupTime.Location()),
-		},
-		{
-			"50 1 2 15 12 ? 2022",
-			StateFound,
-			time.Date(2022, 12, 15, 1, 2, 1, 0, startupTime.Location()),
-			time.Date(2022, 12, 15, 1, 2, 50, 0, startupTime.Location()),
-		},
-	} {
-		s, err := createTestScheduler(tc.expr)
-		if err != nil {
-			t.Errorf("'%s': unexpected error: %#v", tc.expr, err)
-		}
+	// The calendar excludes all days, so that the search stops after the maximum of steps.
+	s.calendar = excludeAllCalendar{}
 
-		tm, state := s.fromNextBestSecond(tc.refTime)
-		if state != tc.state {
-			t.Errorf("'%s': expected '%d', got '%d'", tc.expr, tc.state, state)
-		}
+	tm, state := s.search(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC))
+	if state != StateNoMatches || !tm.IsZero() {
+		t.Errorf("expected '%s', got '%s' (%s)", StateNoMatches, tm, state)
+	}
+}
 
-		if tm.String() != tc.expTime.String() {
-			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.expTime.String(), tm.String())
+func TestSchedule_search_DST(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	s, err := createTestScheduler("0 */20 2 * * ? *")
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+
+	// The clocks are turned back from 03:00 CEST to 02:00 CET, so that 02:xx occurs twice.
+	for _, tc := range []struct {
+		ref time.Time
+		exp time.Time
+	}{
+		{time.Date(2023, 10, 29, 0, 30, 0, 0, time.UTC), time.Date(2023, 10, 29, 0, 40, 0, 0, time.UTC)},
+		{time.Date(2023, 10, 29, 1, 30, 0, 0, time.UTC), time.Date(2023, 10, 29, 1, 40, 0, 0, time.UTC)},
+	} {
+		tm, state := s.search(tc.ref.In(berlin))
+		if state != StateFound || !tm.Equal(tc.exp) {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.ref.In(berlin), tc.exp.In(berlin), tm)
 		}
 	}
+
+	// The clocks are turned forward from 02:00 CET to 03:00 CEST, so that 02:xx does not exist.
+	tm, _ := s.search(time.Date(2023, 3, 26, 1, 0, 0, 0, berlin))
+	if exp := time.Date(2023, 3, 26, 3, 0, 0, 0, berlin); !tm.Equal(exp) {
+		t.Errorf("expected '%s', got '%s'", exp, tm)
+	}
 }
 
 /* ==================================================================================================== */
 
-func TestSchedule_getDaysValuesFromDoM(t *testing.T) {
+func TestSchedule_getDaysFromDoM(t *testing.T) {
 	type testCase struct {
 		expr string
 		t    time.Time
@@ -354,14 +482,14 @@ func TestSchedule_getDaysValuesFromDoM(t *testing.T) {
 			t.Errorf("'%s': unexpected error: %#v", tc.expr, err)
 		}
 
-		got := s.getDaysValues(tc.t)
+		got := bitset{s.getDays(tc.t)}.values()
 		if !reflect.DeepEqual(tc.exp, got) {
 			t.Errorf("'%s': expected '%#v', got '%#v'", tc.expr, tc.exp, got)
 		}
 	}
 }
 
-func TestSchedule_getDaysValuesFromDoW(t *testing.T) {
+func TestSchedule_getDaysFromDoW(t *testing.T) {
 	type testCase struct {
 		expr string
 		t    time.Time
@@ -380,7 +508,7 @@ func TestSchedule_getDaysValuesFromDoW(t *testing.T) {
 			t.Errorf("'%s': unexpected error: %#v", tc.expr, err)
 		}
 
-		got := s.getDaysValues(tc.t)
+		got := bitset{s.getDays(tc.t)}.values()
 		if !reflect.DeepEqual(tc.exp, got) {
 			t.Errorf("'%s': expected '%#v', got '%#v'", tc.expr, tc.exp, got)
 		}
@@ -428,7 +556,7 @@ func TestSchedule_expressionFromMacro(t *testing.T) {
 /* ==================================================================================================== */
 
 func createTestScheduler(expression string) (*schedule, error) {
-	fields, err := getFields(expression)
+	fields, err := getFields(expression, newOptions(nil))
 	if err != nil {
 		return nil, err
 	}
@@ -439,3 +567,9 @@ func createTestScheduler(expression string) (*schedule, error) {
 
 	return s, nil
 }
+
+func matchesSpecByNext(sp spec, t time.Time) bool {
+	next, state := sp.next(t.Add(-resolution))
+
+	return state == StateFound && next.Equal(t)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/alex-schneider/cron"
 )
@@ -36,7 +37,7 @@ testLoop:
 	for {
 		select {
 		case job := <-ch:
-			if job.State == int(cron.StateFound) {
+			if job.State == cron.StateFound {
 				i++ // Trigger some job...
 			}
 
@@ -69,7 +70,7 @@ testLoop:
 	for {
 		select {
 		case job := <-ch:
-			if job.State != int(cron.StateOnceExec) {
+			if job.State != cron.StateOnceExec {
 				t.Errorf("Unexpected state '%#v'", job.State)
 			}
 
@@ -79,3 +80,135 @@ testLoop:
 		}
 	}
 }
+
+func TestSchedule_NewJobCh_Job(t *testing.T) {
+	ctx, cancelFn := context.WithTimeout(context.TODO(), 5*time.Second)
+	defer cancelFn()
+
+	ch, err := cron.NewJobCh(ctx, " * * * * * * * ", cron.WithName("tick"))
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+
+	var upcoming time.Time
+
+	for seq := uint64(0); seq <= 2; seq++ {
+		var job *cron.Job
+
+		select {
+		case job = <-ch:
+		case <-ctx.Done():
+			t.Fatalf("Unexpected 'ctx.Done()'")
+		}
+
+		// The first execution is announced right after the start.
+		if seq == 0 {
+			if job.State != cron.StateScheduled || !job.Next.Equal(job.Upcoming) {
+				t.Errorf("expected '%s', got '%s'", cron.StateScheduled, job.State)
+			}
+
+			upcoming = job.Upcoming
+
+			continue
+		}
+
+		if job.State != cron.StateFound || job.Seq != seq {
+			t.Errorf("expected '%s' and '%d', got '%s' and '%d'", cron.StateFound, seq, job.State, job.Seq)
+		}
+
+		if !job.Scheduled.Equal(upcoming) || !job.Next.Equal(job.Upcoming) {
+			t.Errorf("expected '%s', got '%s'", upcoming, job.Scheduled)
+		}
+
+		upcoming = job.Upcoming
+
+		if job.Expression != "* * * * * * *" || job.Name != "tick" {
+			t.Errorf("expected '%s' and '%s', got '%s' and '%s'", "* * * * * * *", "tick", job.Expression, job.Name)
+		}
+
+		if !job.Scheduled.Equal(job.Scheduled.Truncate(time.Second)) || !job.Upcoming.After(job.Dispatched) {
+			t.Errorf("unexpected times '%s' and '%s'", job.Scheduled, job.Upcoming)
+		}
+
+		// The executions between both times are skipped, if the test is delayed.
+		if skipped := int(job.Upcoming.Sub(job.Scheduled)/time.Second) - 1; job.Skipped != skipped {
+			t.Errorf("expected '%d', got '%d'", skipped, job.Skipped)
+		}
+
+		if job.Lateness != job.Dispatched.Sub(job.Scheduled) {
+			t.Errorf("expected '%s', got '%s'", job.Dispatched.Sub(job.Scheduled), job.Lateness)
+		}
+	}
+}
+
+var benchmarkExpressions = []string{
+	// Typical expressions.
+	"* * * * * * *",
+	"0 */5 9-17 ? * MON-FRI *",
+	"0 0 0 ? * 5L *",
+	"0 0 12 LW * ? *",
+	"250 0 0 12 * * ? *",
+	"0 0 0 ? * 1#1 * week=1-26",
+	// Pathological expressions, which match rarely or never.
+	"0 0 0 29 2 ? *",
+	"0 0 0 ? 2 1#5 *",
+	"59 59 23 31 12 ? 2100/50",
+	"0 0 0 29 2 ? 2021-2099",
+}
+
+func TestSchedule_Next_Allocs(t *testing.T) {
+	ref := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, expr := range benchmarkExpressions {
+		s, err := cron.Parse(expr)
+		if err != nil {
+			t.Fatalf("'%s': unexpected error '%s'", expr, err)
+		}
+
+		if allocs := testing.AllocsPerRun(100, func() { s.Next(ref) }); allocs != 0 {
+			t.Errorf("'%s': expected '%d', got '%f'", expr, 0, allocs)
+		}
+	}
+}
+
+func BenchmarkSchedule_Next(b *testing.B) {
+	ref := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	for _, expr := range benchmarkExpressions {
+		s, err := cron.Parse(expr)
+		if err != nil {
+			b.Fatalf("'%s': unexpected error '%s'", expr, err)
+		}
+
+		b.Run(expr, func(b *testing.B) {
+			b.ReportAllocs()
+
+			next := ref
+
+			for i := 0; i < b.N; i++ {
+				if next = s.Next(next); next.IsZero() {
+					next = ref
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSchedule_Matches(b *testing.B) {
+	ref := time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC)
+
+	for _, expr := range benchmarkExpressions {
+		s, err := cron.Parse(expr)
+		if err != nil {
+			b.Fatalf("'%s': unexpected error '%s'", expr, err)
+		}
+
+		b.Run(expr, func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				s.Matches(ref)
+			}
+		})
+	}
+}
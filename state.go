@@ -4,20 +4,22 @@ package cron
 
 const (
 	// StateFound is returned if a new time could be found for the next execution.
-	StateFound state = iota
+	StateFound State = iota
 	// StateOnceExec is returned if the expression is defined as `@reboot`.
 	StateOnceExec
 	// StateNoMatches is returned if a new time could not be found for the next execution.
 	StateNoMatches
 	// StateZeroTime is returned if the given reference time is zero.
 	StateZeroTime
+	// StateScheduled is sent once after the start of <cron.NewJobCh> with the first execution.
+	StateScheduled
 )
 
-// State represents an accured case while the execution of the <cron.next> method.
-type state int
+// State represents an accured case while the calculation of the next execution, see <cron.Job>.
+type State int
 
 // String implements the <fmt.Stringer> interface.
-func (s state) String() string {
+func (s State) String() string {
 	switch s {
 	case StateFound:
 		return "found"
@@ -27,6 +29,8 @@ func (s state) String() string {
 		return "no-matches"
 	case StateZeroTime:
 		return "zero-time"
+	case StateScheduled:
+		return "scheduled"
 	}
 
 	// Code cannot be reached in the production code...
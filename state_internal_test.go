@@ -3,7 +3,7 @@ package cron
 import "testing"
 
 func TestState_String(t *testing.T) {
-	var s state
+	var s State
 
 	s = StateFound
 	if s.String() != "found" {
@@ -25,6 +25,11 @@ func TestState_String(t *testing.T) {
 		t.Errorf("expected 'zero-time', got '%s'", s.String())
 	}
 
+	s = StateScheduled
+	if s.String() != "scheduled" {
+		t.Errorf("expected 'scheduled', got '%s'", s.String())
+	}
+
 	// Code cannot be reached in the production code...
 	s = -1
 	if s.String() != "unknown" {
@@ -0,0 +1,258 @@
+// Copyright 2022 Alex Schneider. All rights reserved.
+
+package cron
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// systemdShorthands contains the shorthands of the systemd calendar events.
+var systemdShorthands = map[string]string{
+	"minutely":     "*-*-* *:*:00",
+	"hourly":       "*-*-* *:00:00",
+	"daily":        "*-*-* 00:00:00",
+	"weekly":       "Mon *-*-* 00:00:00",
+	"monthly":      "*-*-01 00:00:00",
+	"quarterly":    "*-01,04,07,10-01 00:00:00",
+	"semiannually": "*-01,07-01 00:00:00",
+	"yearly":       "*-01-01 00:00:00",
+	"annually":     "*-01-01 00:00:00",
+}
+
+// systemdDoWNames maps the full names of the days of the week to their short names.
+var systemdDoWNames = map[string]string{
+	"MONDAY": "MON", "TUESDAY": "TUE", "WEDNESDAY": "WED", "THURSDAY": "THU",
+	"FRIDAY": "FRI", "SATURDAY": "SAT", "SUNDAY": "SUN",
+}
+
+/* ==================================================================================================== */
+
+// fromSystemd converts the given systemd calendar event (see `OnCalendar=` in systemd.time(7))
+// like `Mon..Fri *-*-* 09:00:00 Europe/Berlin` to the syntax of this package. The location
+// is returned separately and is nil if the calendar event has no timezone.
+func fromSystemd(expression string) (string, *time.Location, error) {
+	parts := strings.Fields(expression)
+	if len(parts) == 0 {
+		return "", nil, fmt.Errorf("invalid systemd calendar event given '%s'", expression)
+	}
+
+	var location *time.Location
+
+	// The timezone is always the last part, e.g. `UTC` or `Europe/Berlin`.
+	if last := parts[len(parts)-1]; len(parts) > 1 && isLetter(last[0]) {
+		if loc, err := time.LoadLocation(last); err == nil {
+			location = loc
+			parts = parts[:len(parts)-1]
+		}
+	}
+
+	if len(parts) == 1 {
+		if e, ok := systemdShorthands[strings.ToLower(parts[0])]; ok {
+			parts = strings.Fields(e)
+		}
+	}
+
+	dow, date, clock := "*", "*-*-*", "00:00:00"
+
+	if len(parts) > 0 && isLetter(parts[0][0]) {
+		dow, parts = parts[0], parts[1:]
+	}
+
+	if len(parts) > 0 && !strings.Contains(parts[0], ":") {
+		date, parts = parts[0], parts[1:]
+	}
+
+	if len(parts) > 0 {
+		clock, parts = parts[0], parts[1:]
+	}
+
+	if len(parts) > 0 {
+		return "", nil, fmt.Errorf("invalid systemd calendar event given '%s'", expression)
+	}
+
+	dateParts, err := fromSystemdDate(date)
+	if err != nil {
+		return "", nil, err
+	}
+
+	timeParts, err := fromSystemdTime(clock)
+	if err != nil {
+		return "", nil, err
+	}
+
+	dow, err = fromSystemdDoW(dow)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// seconds, minutes, hours, dom, month, dow, year (and optional milliseconds at first).
+	fieldsParts := []string{timeParts[2], timeParts[1], timeParts[0], dateParts[2], dateParts[1], dow, dateParts[0]}
+	if timeParts[3] != "0" {
+		fieldsParts = append([]string{timeParts[3]}, fieldsParts...)
+	}
+
+	return strings.Join(fieldsParts, " "), location, nil
+}
+
+// fromSystemdDate converts the given date like `*-*-01` or `*-02~01` to
+// the year, month and DoM fields.
+func fromSystemdDate(date string) ([]string, error) {
+	var parts []string
+
+	if i := strings.Index(date, "~"); i >= 0 {
+		// The `~` stands for the last days of the month, only the last day is supported.
+		if n, err := strconv.Atoi(date[i+1:]); err != nil || n != 1 {
+			return nil, fmt.Errorf("only the last day of month '~01' is supported in systemd calendar events, given '%s'", date)
+		}
+
+		parts = append(strings.Split(date[:i], "-"), "L")
+	} else {
+		parts = strings.Split(date, "-")
+	}
+
+	switch len(parts) {
+	case 2:
+		parts = append([]string{"*"}, parts...)
+	case 3:
+	default:
+		return nil, fmt.Errorf("invalid date in systemd calendar event given '%s'", date)
+	}
+
+	for i, part := range parts {
+		if part == "L" {
+			continue
+		}
+
+		v, err := fromSystemdComponent(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date in systemd calendar event given '%s'", date)
+		}
+
+		parts[i] = v
+	}
+
+	return parts, nil
+}
+
+// fromSystemdTime converts the given time like `09:00` or `*:0/15:30.5` to
+// the hours, minutes, seconds and milliseconds fields.
+func fromSystemdTime(clock string) ([]string, error) {
+	parts := strings.Split(clock, ":")
+
+	switch len(parts) {
+	case 2:
+		parts = append(parts, "00")
+	case 3:
+	default:
+		return nil, fmt.Errorf("invalid time in systemd calendar event given '%s'", clock)
+	}
+
+	millis := "0"
+
+	// Only a single value of seconds can have a fraction, e.g. `05.250`.
+	if i := strings.Index(parts[2], "."); i >= 0 {
+		fraction := parts[2][i+1:]
+		if len(fraction) == 0 || len(fraction) > 3 {
+			return nil, fmt.Errorf("invalid time in systemd calendar event given '%s'", clock)
+		}
+
+		ms, err := strconv.Atoi((fraction + "00")[:3])
+		if err != nil {
+			return nil, fmt.Errorf("invalid time in systemd calendar event given '%s'", clock)
+		}
+
+		if _, err := strconv.Atoi(parts[2][:i]); err != nil {
+			return nil, fmt.Errorf("invalid time in systemd calendar event given '%s'", clock)
+		}
+
+		parts[2], millis = parts[2][:i], strconv.Itoa(ms)
+	}
+
+	for i, part := range parts {
+		v, err := fromSystemdComponent(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time in systemd calendar event given '%s'", clock)
+		}
+
+		parts[i] = v
+	}
+
+	return append(parts, millis), nil
+}
+
+// fromSystemdDoW converts the given days of the week like `Mon..Fri,Sun` to the DoW field.
+func fromSystemdDoW(dow string) (string, error) {
+	if dow == "*" {
+		return dow, nil
+	}
+
+	parts := strings.Split(strings.ToUpper(dow), ",")
+
+	for i, part := range parts {
+		names := strings.Split(part, "..")
+		if len(names) > 2 {
+			return "", fmt.Errorf("invalid day of week in systemd calendar event given '%s'", dow)
+		}
+
+		for j, name := range names {
+			if short, ok := systemdDoWNames[name]; ok {
+				names[j] = short
+			} else if _, ok := dowMap[name]; !ok {
+				return "", fmt.Errorf("invalid day of week in systemd calendar event given '%s'", dow)
+			}
+		}
+
+		parts[i] = strings.Join(names, "-")
+	}
+
+	return strings.Join(parts, ","), nil
+}
+
+// fromSystemdComponent converts a single component of the date or the time
+// like `*`, `1,15`, `9..17` or `0/15` to the syntax of this package.
+func fromSystemdComponent(component string) (string, error) {
+	parts := strings.Split(component, ",")
+
+	for i, part := range parts {
+		value, step, hasStep := strings.Cut(part, "/")
+
+		if hasStep {
+			if _, err := strconv.Atoi(step); err != nil {
+				return "", err
+			}
+
+			step = "/" + step
+		}
+
+		if value == "*" {
+			parts[i] = value + step
+
+			continue
+		}
+
+		values := strings.Split(value, "..")
+		if len(values) > 2 {
+			return "", fmt.Errorf("invalid component given '%s'", component)
+		}
+
+		for j, v := range values {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return "", err
+			}
+
+			values[j] = strconv.Itoa(n)
+		}
+
+		parts[i] = strings.Join(values, "-") + step
+	}
+
+	return strings.Join(parts, ","), nil
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
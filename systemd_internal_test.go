@@ -0,0 +1,88 @@
+package cron
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSystemd_fromSystemd(t *testing.T) {
+	type testCase struct {
+		expr     string
+		exp      string
+		location string
+		err      string
+	}
+
+	for _, tc := range []testCase{
+		{"Mon..Fri *-*-* 09:00:00", "0 0 9 * * MON-FRI *", "", ``},
+		{"*-*-01 04:00:00 Europe/Berlin", "0 0 4 1 * * *", "Europe/Berlin", ``},
+		{"Saturday,Sun 2023-01..06-* 8..17:0/15", "0 0/15 8-17 * 1-6 SAT,SUN 2023", "", ``},
+		{"*-02~01 10:00", "0 0 10 L 2 * *", "", ``},
+		{"12:30:05.25", "250 5 30 12 * * * *", "", ``},
+		{"Mon", "0 0 0 * * MON *", "", ``},
+		{"weekly", "0 0 0 * * MON *", "", ``},
+		{"Quarterly UTC", "0 0 0 1 1,4,7,10 * *", "UTC", ``},
+		{"", "", "", `invalid systemd calendar event given ''`},
+		{"*-*-* 00:00 UTC X", "", "", `invalid systemd calendar event given '*-*-* 00:00 UTC X'`},
+		{"*-02~03", "", "", `only the last day of month '~01' is supported in systemd calendar events, given '*-02~03'`},
+		{"*-1-2-3-4", "", "", `invalid date in systemd calendar event given '*-1-2-3-4'`},
+		{"*-*-x", "", "", `invalid date in systemd calendar event given '*-*-x'`},
+		{"12:", "", "", `invalid time in systemd calendar event given '12:'`},
+		{"*:*:*.5", "", "", `invalid time in systemd calendar event given '*:*:*.5'`},
+		{"00:00:00.1234", "", "", `invalid time in systemd calendar event given '00:00:00.1234'`},
+		{"Mon..Tue..Wed", "", "", `invalid day of week in systemd calendar event given 'Mon..Tue..Wed'`},
+		{"Foo 12:00", "", "", `invalid day of week in systemd calendar event given 'Foo'`},
+	} {
+		got, location, err := fromSystemd(tc.expr)
+		if got != tc.exp {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.exp, got)
+		}
+
+		if gotLocation := fmt.Sprint(location); location != nil && gotLocation != tc.location {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.location, gotLocation)
+		} else if location == nil && tc.location != "" {
+			t.Errorf("'%s': expected '%s', got nil", tc.expr, tc.location)
+		}
+
+		if tc.err != "" || err != nil {
+			if eerr := fmt.Sprintf("%s", err); tc.err != eerr {
+				t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.err, eerr)
+			}
+		}
+	}
+}
+
+func TestSystemd_WithDialect(t *testing.T) {
+	type testCase struct {
+		expr string
+		exp  time.Time
+	}
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+
+	referenceTime := time.Date(2022, 12, 31, 23, 59, 59, 0, time.UTC)
+
+	for _, tc := range []testCase{
+		{"Mon..Fri *-*-* 09:00:00", time.Date(2023, 1, 2, 9, 0, 0, 0, time.UTC)},
+		// 2022-12-31 23:59:59 UTC is already 2023-01-01 in Berlin.
+		{"*-*-01 04:00:00 Europe/Berlin", time.Date(2023, 1, 1, 4, 0, 0, 0, berlin)},
+		// The DoW and the date must both match.
+		{"Sat *-*-01 12:00", time.Date(2023, 4, 1, 12, 0, 0, 0, time.UTC)},
+		{"*-02~01 10:00", time.Date(2023, 2, 28, 10, 0, 0, 0, time.UTC)},
+		{"2024-*-* 00:00:05.5", time.Date(2024, 1, 1, 0, 0, 5, int(500*time.Millisecond), time.UTC)},
+		{"daily", time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)},
+	} {
+		s, err := Parse(tc.expr, WithDialect(DialectSystemd))
+		if err != nil {
+			t.Fatalf("'%s': unexpected error: %#v", tc.expr, err)
+		}
+
+		if got := s.Next(referenceTime); got.String() != tc.exp.String() {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.exp, got)
+		}
+	}
+}
@@ -10,6 +10,9 @@ const (
 	typeMonth
 	typeDoW
 	typeYear
+	typeWeek
+	typeDoY
+	typeMilliseconds
 )
 
 type fieldType int
@@ -33,6 +36,12 @@ func (ft fieldType) String() string {
 		return "day-of-week"
 	case typeYear:
 		return "year"
+	case typeWeek:
+		return "week-of-year"
+	case typeDoY:
+		return "day-of-year"
+	case typeMilliseconds:
+		return "milliseconds"
 	}
 
 	// Code cannot be reached in the production code...
@@ -40,6 +40,21 @@ func TestTypes_String(t *testing.T) {
 		t.Errorf("expected 'year', got '%s'", ft.String())
 	}
 
+	ft = typeWeek
+	if ft.String() != "week-of-year" {
+		t.Errorf("expected 'week-of-year', got '%s'", ft.String())
+	}
+
+	ft = typeDoY
+	if ft.String() != "day-of-year" {
+		t.Errorf("expected 'day-of-year', got '%s'", ft.String())
+	}
+
+	ft = typeMilliseconds
+	if ft.String() != "milliseconds" {
+		t.Errorf("expected 'milliseconds', got '%s'", ft.String())
+	}
+
 	// Code cannot be reached in the production code...
 	ft = -1
 	if ft.String() != "unknown" {
@@ -20,6 +20,10 @@ var monthMap = map[string]int{
 	"JUL": 7, "AUG": 8, "SEP": 9, "OCT": 10, "NOV": 11, "DEC": 12,
 }
 
+// randomYears is the number of the years from the year of the reference time on, from which
+// the `R` special character in the year field picks a year.
+const randomYears = 10
+
 var tableValues = []int{
 	0, 1, 2, 3, 4, 5, 6, 7, 8, 9,
 	10, 11, 12, 13, 14, 15, 16, 17, 18, 19,
@@ -29,22 +33,6 @@ var tableValues = []int{
 	50, 51, 52, 53, 54, 55, 56, 57, 58, 59,
 }
 
-var yearValues = []int{
-	1970, 1971, 1972, 1973, 1974, 1975, 1976, 1977, 1978, 1979,
-	1980, 1981, 1982, 1983, 1984, 1985, 1986, 1987, 1988, 1989,
-	1990, 1991, 1992, 1993, 1994, 1995, 1996, 1997, 1998, 1999,
-	2000, 2001, 2002, 2003, 2004, 2005, 2006, 2007, 2008, 2009,
-	2010, 2011, 2012, 2013, 2014, 2015, 2016, 2017, 2018, 2019,
-	2020, 2021, 2022, 2023, 2024, 2025, 2026, 2027, 2028, 2029,
-	2030, 2031, 2032, 2033, 2034, 2035, 2036, 2037, 2038, 2039,
-	2040, 2041, 2042, 2043, 2044, 2045, 2046, 2047, 2048, 2049,
-	2050, 2051, 2052, 2053, 2054, 2055, 2056, 2057, 2058, 2059,
-	2060, 2061, 2062, 2063, 2064, 2065, 2066, 2067, 2068, 2069,
-	2070, 2071, 2072, 2073, 2074, 2075, 2076, 2077, 2078, 2079,
-	2080, 2081, 2082, 2083, 2084, 2085, 2086, 2087, 2088, 2089,
-	2090, 2091, 2092, 2093, 2094, 2095, 2096, 2097, 2098, 2099,
-}
-
 var (
 	startupTime                = time.Now()
 	dowList                    = "SUN|MON|TUE|WED|THU|FRI|SAT|SUN"
@@ -63,14 +51,14 @@ var (
 
 /* ==================================================================================================== */
 
-func getFlexValues(expr string, ft fieldType) ([]int, string, error) {
+func getFlexValues(expr string, ft fieldType, o *options) ([]int, string, error) {
 	if err := getFlexValuesError(expr, ft); err != nil {
 		return nil, "", err
 	}
 
 	// `R`
 	if expr == "R" {
-		return getRandomValues(ft), "", nil
+		return getRandomValues(ft, o), "", nil
 	}
 
 	// `L`
@@ -139,51 +127,83 @@ func getFlexValuesError(expr string, ft fieldType) error {
 	return nil
 }
 
-func getFixValues(expr string, ft fieldType) ([]int, error) {
+func getFixValues(expr string, ft fieldType, o *options) ([]int, error) {
 	// `*`
 	if expr == "*" {
-		return getWildcardValues(ft)
+		return getWildcardValues(ft, o)
 	}
 
 	// `.`
 	if expr == "." {
-		return getCurrentTimeValues(ft)
+		return getCurrentTimeValues(ft, o)
 	}
 
 	// `3`, `NOV`, `FRI`, `2020`
 	if match := reSingleValue.FindString(expr); match != "" {
-		return getSingleValue(match, ft)
+		return getSingleValue(match, ft, o)
 	}
 
 	// `3-5`, `APR-JUL`, `2020-2035`, `MON-5` (eq. `MON-FRI`)
 	if matches := reRangeValue.FindStringSubmatch(expr); len(matches) == 3 {
-		return getRangeValues(matches[1], matches[2], false, ft)
+		return getRangeValues(matches[1], matches[2], false, ft, o)
 	}
 
 	// `3-5/R`, `APR-JUL/R`, `2020-2035/R`, `MON-5/R` (eq. `MON-FRI/R`)
 	if matches := reRangeRandomValue.FindStringSubmatch(expr); len(matches) == 3 {
-		return getRangeValues(matches[1], matches[2], true, ft)
+		return getRangeValues(matches[1], matches[2], true, ft, o)
 	}
 
 	// `*/5`
 	if matches := reWildcardIntervalValue.FindStringSubmatch(expr); len(matches) == 2 {
-		return getWildcardIntervalValues(matches[1], ft)
+		return getWildcardIntervalValues(matches[1], ft, o)
 	}
 
 	// `10/5`, `MON/2`, `JAN/4`
 	if matches := reSingleValueIntervalValue.FindStringSubmatch(expr); len(matches) == 3 {
-		return getSingleValueIntervalValues(matches[1], matches[2], ft)
+		return getSingleValueIntervalValues(matches[1], matches[2], ft, o)
 	}
 
 	// `10-30/5`, `APR-JUL/2`, `2020-2035/5`, `MON-6/2` (eq. `MON-SAT/2`)
 	if matches := reRangeIntervalValue.FindStringSubmatch(expr); len(matches) == 4 {
-		return getRangeIntervalValues(matches[1], matches[2], matches[3], ft)
+		return getRangeIntervalValues(matches[1], matches[2], matches[3], ft, o)
 	}
 
 	return errValue(expr, ft)
 }
 
-func getRandomValues(ft fieldType) []int {
+// getOpenYearValues returns the first value and the step of an open-ended
+// expression in the year field, e.g. `*`, `*/5` or `2020/5`. Such expressions
+// are not materialized to a list of values.
+func getOpenYearValues(expr string, o *options) ([]int, bool, error) {
+	var start, step int
+
+	if expr == "*" {
+		return []int{o.minYear, 1}, true, nil
+	} else if matches := reWildcardIntervalValue.FindStringSubmatch(expr); len(matches) == 2 {
+		start = o.minYear
+		step, _ = strconv.Atoi(matches[1])
+	} else if matches := reSingleValueIntervalValue.FindStringSubmatch(expr); len(matches) == 3 {
+		values, err := getSingleValue(matches[1], typeYear, o)
+		if err != nil {
+			return nil, true, err
+		}
+
+		start = values[0]
+		step, _ = strconv.Atoi(matches[2])
+	} else {
+		return nil, false, nil
+	}
+
+	if step < 1 {
+		_, err := errValue(expr, typeYear)
+
+		return nil, true, err
+	}
+
+	return []int{start, step}, true, nil
+}
+
+func getRandomValues(ft fieldType, o *options) []int {
 	switch ft {
 	case typeSeconds:
 		fallthrough
@@ -198,20 +218,51 @@ func getRandomValues(ft fieldType) []int {
 	case typeDoW:
 		return []int{random.Intn(6)} // Random 0-6
 	case typeYear:
-		return []int{yearValues[random.Intn(130)]} // Random 1970-2099
+		return []int{randomYear(o)} // Random year of the next 10 years
+	case typeWeek:
+		return []int{random.Intn(52) + 1} // Random 1-52
+	case typeMilliseconds:
+		return []int{random.Intn(1000)} // Random 0-999
+	case typeDoY:
+		return []int{random.Intn(365) + 1} // Random 1-365
 	}
 
 	// Code cannot be reached in the production code...
 	return nil
 }
 
+// randomYear returns a random year of the <cron.randomYears> years from the year of the
+// reference time on, which are limited to the year range. The last years of the range are
+// used if the reference time is after the range.
+func randomYear(o *options) int {
+	min := o.now.Year()
+	if o.location != nil {
+		min = o.now.In(o.location).Year()
+	}
+
+	if min > o.maxYear {
+		min = o.maxYear - randomYears + 1
+	}
+
+	if min < o.minYear {
+		min = o.minYear
+	}
+
+	max := min + randomYears - 1
+	if max > o.maxYear {
+		max = o.maxYear
+	}
+
+	return min + random.Intn(max-min+1)
+}
+
 func errValue(value string, ft fieldType) ([]int, error) {
 	return nil, fmt.Errorf("invalid value in field '%s' given: '%s'", ft, value)
 }
 
 /* ==================================================================================================== */
 
-func getWildcardValues(ft fieldType) ([]int, error) {
+func getWildcardValues(ft fieldType, o *options) ([]int, error) {
 	var values []int
 
 	switch ft {
@@ -230,9 +281,12 @@ func getWildcardValues(ft fieldType) ([]int, error) {
 	case typeDoW:
 		values = make([]int, 7)
 		copy(values, tableValues[:7])
-	case typeYear:
-		values = make([]int, 130)
-		copy(values, yearValues)
+	case typeYear, typeWeek, typeDoY, typeMilliseconds:
+		min, max := getMinMax(ft, o)
+
+		for i := min; i <= max; i++ {
+			values = append(values, i)
+		}
 	default:
 		return nil, fmt.Errorf("unsupported fieldType given: '%s'", ft)
 	}
@@ -240,41 +294,55 @@ func getWildcardValues(ft fieldType) ([]int, error) {
 	return values, nil
 }
 
-func getCurrentTimeValues(ft fieldType) ([]int, error) {
+func getCurrentTimeValues(ft fieldType, o *options) ([]int, error) {
+	// The values are taken in the location of the execution times, see <cron.WithLocation>.
+	now := o.now
+	if o.location != nil {
+		now = now.In(o.location)
+	}
+
 	switch ft {
 	case typeSeconds:
-		return []int{startupTime.Second()}, nil
+		return []int{now.Second()}, nil
 	case typeMinutes:
-		return []int{startupTime.Minute()}, nil
+		return []int{now.Minute()}, nil
 	case typeHours:
-		return []int{startupTime.Hour()}, nil
+		return []int{now.Hour()}, nil
 	case typeDoM:
-		return []int{startupTime.Day()}, nil
+		return []int{now.Day()}, nil
 	case typeMonth:
-		return []int{int(startupTime.Month())}, nil
+		return []int{int(now.Month())}, nil
 	case typeDoW:
-		return []int{int(startupTime.Weekday())}, nil
+		return []int{int(now.Weekday())}, nil
 	case typeYear:
-		return []int{startupTime.Year()}, nil
+		return []int{now.Year()}, nil
+	case typeWeek:
+		_, week := now.ISOWeek()
+
+		return []int{week}, nil
+	case typeDoY:
+		return []int{now.YearDay()}, nil
+	case typeMilliseconds:
+		return []int{now.Nanosecond() / int(time.Millisecond)}, nil
 	}
 
 	// Code cannot be reached in the production code...
 	return nil, fmt.Errorf("unsupported fieldType given: '%s'", ft)
 }
 
-func getSingleValue(value string, ft fieldType) ([]int, error) {
+func getSingleValue(value string, ft fieldType, o *options) ([]int, error) {
 	isDoW, isMonth, isNum, numVal, err := toNumVal(value)
 	if err != nil {
 		return nil, err
 	}
 
 	switch ft {
-	case typeSeconds, typeMinutes, typeHours:
+	case typeSeconds, typeMinutes, typeHours, typeMilliseconds:
 		return getSingleValueFromTime(value, ft, isNum, numVal)
-	case typeDoM, typeDoW:
+	case typeDoM, typeDoW, typeWeek, typeDoY:
 		return getSingleValueFromDayOf(value, ft, isDoW, isNum, numVal)
 	case typeMonth, typeYear:
-		return getSingleValueFromDate(value, ft, isMonth, isNum, numVal)
+		return getSingleValueFromDate(value, ft, isMonth, isNum, numVal, o)
 	}
 
 	// Code cannot be reached in the production code...
@@ -296,6 +364,12 @@ func getSingleValueFromTime(
 			return errValue(value, ft)
 		}
 
+		return []int{numVal}, nil
+	case typeMilliseconds:
+		if !isNum || numVal < 0 || numVal > 999 {
+			return errValue(value, ft)
+		}
+
 		return []int{numVal}, nil
 	}
 
@@ -320,6 +394,18 @@ func getSingleValueFromDayOf(
 			return []int{0}, nil
 		}
 
+		return []int{numVal}, nil
+	case typeWeek:
+		if !isNum || numVal < 1 || numVal > 53 {
+			return errValue(value, ft)
+		}
+
+		return []int{numVal}, nil
+	case typeDoY:
+		if !isNum || numVal < 1 || numVal > 366 {
+			return errValue(value, ft)
+		}
+
 		return []int{numVal}, nil
 	}
 
@@ -328,7 +414,7 @@ func getSingleValueFromDayOf(
 }
 
 func getSingleValueFromDate(
-	value string, ft fieldType, isMonth, isNum bool, numVal int,
+	value string, ft fieldType, isMonth, isNum bool, numVal int, o *options,
 ) ([]int, error) {
 	switch ft {
 	case typeMonth:
@@ -338,7 +424,7 @@ func getSingleValueFromDate(
 
 		return []int{numVal}, nil
 	case typeYear:
-		if !isNum || numVal < 1970 || numVal > 2099 {
+		if !isNum || numVal < o.minYear || numVal > o.maxYear {
 			return errValue(value, ft)
 		}
 
@@ -349,13 +435,13 @@ func getSingleValueFromDate(
 	return nil, fmt.Errorf("unsupported fieldType given: '%s'", ft)
 }
 
-func getRangeValues(v1, v2 string, isRandom bool, ft fieldType) ([]int, error) {
-	numVal1, err := getSingleValue(v1, ft)
+func getRangeValues(v1, v2 string, isRandom bool, ft fieldType, o *options) ([]int, error) {
+	numVal1, err := getSingleValue(v1, ft, o)
 	if err != nil {
 		return nil, err
 	}
 
-	numVal2, err := getSingleValue(v2, ft)
+	numVal2, err := getSingleValue(v2, ft, o)
 	if err != nil {
 		return nil, err
 	}
@@ -367,7 +453,7 @@ func getRangeValues(v1, v2 string, isRandom bool, ft fieldType) ([]int, error) {
 	var values []int
 
 	if numVal1[0] > numVal2[0] {
-		min, max := getMinMax(ft)
+		min, max := getMinMax(ft, o)
 
 		for i := min; i <= numVal2[0]; i++ {
 			values = append(values, i)
@@ -388,14 +474,14 @@ func getRangeValues(v1, v2 string, isRandom bool, ft fieldType) ([]int, error) {
 	return values, nil
 }
 
-func getWildcardIntervalValues(interval string, ft fieldType) ([]int, error) {
+func getWildcardIntervalValues(interval string, ft fieldType, o *options) ([]int, error) {
 	step, err := strconv.Atoi(interval)
 	if err != nil {
 		return nil, err
 	}
 
 	var values []int
-	min, max := getMinMax(ft)
+	min, max := getMinMax(ft, o)
 
 	for i := min; i <= max; i += step {
 		values = append(values, i)
@@ -404,19 +490,19 @@ func getWildcardIntervalValues(interval string, ft fieldType) ([]int, error) {
 	return values, nil
 }
 
-func getSingleValueIntervalValues(value, interval string, ft fieldType) ([]int, error) {
+func getSingleValueIntervalValues(value, interval string, ft fieldType, o *options) ([]int, error) {
 	step, err := strconv.Atoi(interval)
 	if err != nil {
 		return nil, err
 	}
 
-	nums, err := getSingleValue(value, ft)
+	nums, err := getSingleValue(value, ft, o)
 	if err != nil {
 		return nil, err
 	}
 
 	var values []int
-	_, max := getMinMax(ft)
+	_, max := getMinMax(ft, o)
 
 	for i := nums[0]; i <= max; i += step {
 		values = append(values, i)
@@ -425,18 +511,18 @@ func getSingleValueIntervalValues(value, interval string, ft fieldType) ([]int,
 	return values, nil
 }
 
-func getRangeIntervalValues(v1, v2, interval string, ft fieldType) ([]int, error) {
+func getRangeIntervalValues(v1, v2, interval string, ft fieldType, o *options) ([]int, error) {
 	step, err := strconv.Atoi(interval)
 	if err != nil {
 		return nil, err
 	}
 
-	numVal1, err := getSingleValue(v1, ft)
+	numVal1, err := getSingleValue(v1, ft, o)
 	if err != nil {
 		return nil, err
 	}
 
-	numVal2, err := getSingleValue(v2, ft)
+	numVal2, err := getSingleValue(v2, ft, o)
 	if err != nil {
 		return nil, err
 	}
@@ -448,7 +534,7 @@ func getRangeIntervalValues(v1, v2, interval string, ft fieldType) ([]int, error
 	var values []int
 
 	if numVal1[0] > numVal2[0] {
-		list, _ := getWildcardValues(ft)
+		list, _ := getWildcardValues(ft, o)
 
 		var i int
 		var running bool
@@ -485,7 +571,7 @@ func getRangeIntervalValues(v1, v2, interval string, ft fieldType) ([]int, error
 
 /* ==================================================================================================== */
 
-func getMinMax(ft fieldType) (int, int) {
+func getMinMax(ft fieldType, o *options) (int, int) {
 	var min, max int
 
 	switch ft {
@@ -505,8 +591,17 @@ func getMinMax(ft fieldType) (int, int) {
 		min = 0
 		max = 6
 	case typeYear:
-		min = 1970
-		max = 2099
+		min = o.minYear
+		max = o.maxYear
+	case typeWeek:
+		min = 1
+		max = 53
+	case typeDoY:
+		min = 1
+		max = 366
+	case typeMilliseconds:
+		min = 0
+		max = 999
 	}
 
 	// Code cannot be reached in the production code...
@@ -550,25 +645,29 @@ func toNumVal(value string) (bool, bool, bool, int, error) {
 	return isDoW, isMonth, isNum, numVal, nil
 }
 
+func containsValue(values []int, value int) bool {
+	i := sort.SearchInts(values, value)
+
+	return i < len(values) && values[i] == value
+}
+
+// uniqueValues returns the sorted values without duplicates. The given values are not modified.
 func uniqueValues(values []int) []int {
-	var unique []int
+	if len(values) == 0 {
+		return nil
+	}
 
-	for _, i := range values {
-		var exists bool
+	sorted := make([]int, len(values))
+	copy(sorted, values)
+	sort.Ints(sorted)
 
-		for _, j := range unique {
-			if i == j {
-				exists = true
-				break
-			}
-		}
+	unique := sorted[:1]
 
-		if !exists {
-			unique = append(unique, i)
+	for _, v := range sorted[1:] {
+		if v != unique[len(unique)-1] {
+			unique = append(unique, v)
 		}
 	}
 
-	sort.Ints(unique)
-
 	return unique
 }
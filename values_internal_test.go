@@ -2,10 +2,15 @@ package cron
 
 import (
 	"fmt"
+	"math"
 	"reflect"
 	"testing"
+	"time"
 )
 
+// testOptions limits the year field to the range 1970-2099.
+var testOptions = newOptions([]Option{WithYearRange(1970, 2099)})
+
 func TestValues_getFlexValues(t *testing.T) {
 	type testCase struct {
 		expr string
@@ -32,7 +37,7 @@ func TestValues_getFlexValues(t *testing.T) {
 		{"FRI#3", typeDoW, []int{5, 3}, "#", ``},
 		{"XXX", typeDoM, nil, "", `unsupported expression value given: 'XXX'`},
 	} {
-		got, unit, err := getFlexValues(tc.expr, tc.ft)
+		got, unit, err := getFlexValues(tc.expr, tc.ft, testOptions)
 		if !reflect.DeepEqual(tc.expV, got) {
 			t.Errorf("'%s': expected '%#v', got '%#v'", tc.expr, tc.expV, got)
 		}
@@ -67,7 +72,7 @@ func TestValues_getFixValues(t *testing.T) {
 		{"10-20/5", typeMinutes, []int{10, 15, 20}, ``},
 		{"XXX", typeYear, nil, `invalid value in field 'year' given: 'XXX'`},
 	} {
-		got, err := getFixValues(tc.expr, tc.ft)
+		got, err := getFixValues(tc.expr, tc.ft, testOptions)
 		if !reflect.DeepEqual(tc.exp, got) {
 			t.Errorf("'%s': expected '%#v', got '%#v'", tc.expr, tc.exp, got)
 		}
@@ -101,7 +106,7 @@ func TestValues_RandomFixValues(t *testing.T) {
 			{"2025-2030/R", typeYear, 2025, 2030, ``},
 			{"1-32/R", typeDoM, 0, 0, `invalid value in field 'day-of-month' given: '32'`},
 		} {
-			got, err := getFixValues(tc.expr, tc.ft)
+			got, err := getFixValues(tc.expr, tc.ft, testOptions)
 			if tc.err != "" || err != nil {
 				if eerr := fmt.Sprintf("%s", err); tc.err != eerr {
 					t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.err, eerr)
@@ -139,9 +144,9 @@ func TestValues_RandomFlexValues(t *testing.T) {
 			{"R", typeDoM, 1, 28},
 			{"R", typeMonth, 1, 12},
 			{"R", typeDoW, 0, 6},
-			{"R", typeYear, 1970, 2099},
+			{"R", typeYear, startupTime.Year(), startupTime.Year() + 9},
 		} {
-			got, unit, err := getFlexValues(tc.expr, tc.ft)
+			got, unit, err := getFlexValues(tc.expr, tc.ft, testOptions)
 			if err != nil {
 				t.Fatal(err)
 			}
@@ -161,6 +166,33 @@ func TestValues_RandomFlexValues(t *testing.T) {
 	}
 }
 
+func TestValues_RandomYear(t *testing.T) {
+	type testCase struct {
+		opts   []Option
+		expMin int
+		expMax int
+	}
+
+	ref := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 1000; i++ {
+		for _, tc := range []testCase{
+			{[]Option{WithReferenceTime(ref)}, 2030, 2039},
+			{[]Option{WithReferenceTime(ref), WithYearRange(2032, 2099)}, 2032, 2041},
+			{[]Option{WithReferenceTime(ref), WithYearRange(1970, 2034)}, 2030, 2034},
+			{[]Option{WithReferenceTime(ref), WithYearRange(1970, 2025)}, 2016, 2025},
+			{[]Option{WithReferenceTime(ref), WithYearRange(2000, 2003)}, 2000, 2003},
+			{[]Option{WithReferenceTime(ref), WithYearRange(-math.MaxInt, math.MaxInt)}, 2030, 2039},
+		} {
+			n := randomYear(newOptions(tc.opts))
+
+			if n < tc.expMin || n > tc.expMax {
+				t.Errorf("Unexpected value given: %d (allowed range: %d-%d)", n, tc.expMin, tc.expMax)
+			}
+		}
+	}
+}
+
 /* ==================================================================================================== */
 
 func TestValues_getWildcardValues(t *testing.T) {
@@ -177,10 +209,9 @@ func TestValues_getWildcardValues(t *testing.T) {
 		{typeDoM, tableValues[1:32], ``},
 		{typeMonth, tableValues[1:13], ``},
 		{typeDoW, tableValues[:7], ``},
-		{typeYear, yearValues[:], ``},
 		{-1, nil, `unsupported fieldType given: 'unknown'`},
 	} {
-		if got, err := getWildcardValues(tc.ft); !reflect.DeepEqual(tc.exp, got) {
+		if got, err := getWildcardValues(tc.ft, testOptions); !reflect.DeepEqual(tc.exp, got) {
 			t.Errorf("'%s': expected '%#v', got '%#v'", tc.ft, tc.exp, got)
 		} else if tc.err != "" || err != nil {
 			if eerr := fmt.Sprintf("%s", err); tc.err != eerr {
@@ -188,6 +219,11 @@ func TestValues_getWildcardValues(t *testing.T) {
 			}
 		}
 	}
+
+	exp := []int{2020, 2021, 2022, 2023}
+	if got, _ := getWildcardValues(typeYear, newOptions([]Option{WithYearRange(2020, 2023)})); !reflect.DeepEqual(exp, got) {
+		t.Errorf("'%s': expected '%#v', got '%#v'", typeYear, exp, got)
+	}
 }
 
 func TestValues_getCurrentTimeValues(t *testing.T) {
@@ -207,7 +243,7 @@ func TestValues_getCurrentTimeValues(t *testing.T) {
 		{typeYear, []int{startupTime.Year()}, ``},
 		{-1, nil, `unsupported fieldType given: 'unknown'`},
 	} {
-		if got, err := getCurrentTimeValues(tc.ft); !reflect.DeepEqual(tc.exp, got) {
+		if got, err := getCurrentTimeValues(tc.ft, testOptions); !reflect.DeepEqual(tc.exp, got) {
 			t.Errorf("'%s': expected '%#v', got '%#v'", tc.ft, tc.exp, got)
 		} else if err != nil {
 			if eerr := fmt.Sprintf("%s", err); tc.err != eerr {
@@ -217,6 +253,50 @@ func TestValues_getCurrentTimeValues(t *testing.T) {
 	}
 }
 
+func TestValues_getCurrentTimeValues_WithReferenceTime(t *testing.T) {
+	ref := time.Date(2023, 5, 6, 14, 30, 15, 250*int(time.Millisecond), time.UTC)
+
+	s, err := Parse("0 . . * * ? *", WithReferenceTime(ref))
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+
+	got := s.Next(time.Date(2023, 5, 7, 0, 0, 0, 0, time.UTC))
+	if exp := time.Date(2023, 5, 7, 14, 30, 0, 0, time.UTC); got.String() != exp.String() {
+		t.Errorf("expected '%s', got '%s'", exp, got)
+	}
+
+	o := newOptions([]Option{WithReferenceTime(ref)})
+
+	for _, tc := range []struct {
+		ft  fieldType
+		exp []int
+	}{
+		{typeMilliseconds, []int{250}},
+		{typeSeconds, []int{15}},
+		{typeDoW, []int{int(time.Saturday)}},
+		{typeWeek, []int{18}},
+		{typeDoY, []int{126}},
+	} {
+		if got, _ := getCurrentTimeValues(tc.ft, o); !reflect.DeepEqual(tc.exp, got) {
+			t.Errorf("'%s': expected '%#v', got '%#v'", tc.ft, tc.exp, got)
+		}
+	}
+
+	// 14:30 UTC is 23:30 in Tokyo.
+	tokyo := time.FixedZone("Asia/Tokyo", 9*3600)
+
+	s, err = Parse("0 . . * * ? *", WithReferenceTime(ref), WithLocation(tokyo))
+	if err != nil {
+		t.Fatalf("unexpected error: %#v", err)
+	}
+
+	got = s.Next(time.Date(2023, 5, 7, 0, 0, 0, 0, tokyo))
+	if exp := time.Date(2023, 5, 7, 23, 30, 0, 0, tokyo); got.String() != exp.String() {
+		t.Errorf("expected '%s', got '%s'", exp, got)
+	}
+}
+
 func TestValues_getSingleValue(t *testing.T) {
 	type testCase struct {
 		val string
@@ -273,7 +353,7 @@ func TestValues_getSingleValue(t *testing.T) {
 		{"2100", typeYear, nil, `invalid value in field 'year' given: '2100'`},
 		{"2020", typeYear, []int{2020}, ``},
 	} {
-		got, err := getSingleValue(tc.val, tc.ft)
+		got, err := getSingleValue(tc.val, tc.ft, testOptions)
 		if !reflect.DeepEqual(tc.exp, got) {
 			t.Errorf("'%s': expected '%#v', got '%#v'", tc.val, tc.exp, got)
 		}
@@ -305,7 +385,7 @@ func TestValues_getSingleValueFromDayOf(t *testing.T) {
 }
 
 func TestValues_getSingleValueFromDate(t *testing.T) {
-	_, err := getSingleValueFromDate("XXX", typeHours, true, true, 0)
+	_, err := getSingleValueFromDate("XXX", typeHours, true, true, 0, testOptions)
 
 	exp := `unsupported fieldType given: 'hours'`
 	if eerr := fmt.Sprintf("%s", err); eerr != exp {
@@ -367,7 +447,7 @@ func TestValues_getRangeValues(t *testing.T) {
 		{"2020", "2010", typeYear, nil, `invalid value in field 'year' given: '2020-2010'`},
 		{"2020", "2020", typeYear, []int{2020}, ``},
 	} {
-		got, err := getRangeValues(tc.v1, tc.v2, false, tc.ft)
+		got, err := getRangeValues(tc.v1, tc.v2, false, tc.ft, testOptions)
 		if !reflect.DeepEqual(tc.exp, got) {
 			t.Errorf("'%s/%s': expected '%#v', got '%#v'", tc.v1, tc.v2, tc.exp, got)
 		}
@@ -415,7 +495,7 @@ func TestValues_getWildcardIntervalValues(t *testing.T) {
 		// Year
 		{"20", typeYear, []int{1970, 1990, 2010, 2030, 2050, 2070, 2090}, ``},
 	} {
-		got, err := getWildcardIntervalValues(tc.val, tc.ft)
+		got, err := getWildcardIntervalValues(tc.val, tc.ft, testOptions)
 		if !reflect.DeepEqual(tc.exp, got) {
 			t.Errorf("'%s': expected '%#v', got '%#v'", tc.val, tc.exp, got)
 		}
@@ -472,7 +552,7 @@ func TestValues_getSingleValueIntervalValues(t *testing.T) {
 		{"2020", "20", typeYear, []int{2020, 2040, 2060, 2080}, ``},
 		{"2020", "100", typeYear, []int{2020}, ``},
 	} {
-		got, err := getSingleValueIntervalValues(tc.val, tc.step, tc.ft)
+		got, err := getSingleValueIntervalValues(tc.val, tc.step, tc.ft, testOptions)
 		if !reflect.DeepEqual(tc.exp, got) {
 			t.Errorf("'%s/%s': expected '%#v', got '%#v'", tc.val, tc.step, tc.exp, got)
 		}
@@ -541,7 +621,7 @@ func TestValues_getRangeIntervalValues(t *testing.T) {
 		{"2020", "2010", "5", typeYear, nil, `invalid value in field 'year' given: '2020-2010'`},
 		{"2020", "2020", "5", typeYear, []int{2020}, ``},
 	} {
-		got, err := getRangeIntervalValues(tc.v1, tc.v2, tc.step, tc.ft)
+		got, err := getRangeIntervalValues(tc.v1, tc.v2, tc.step, tc.ft, testOptions)
 		if !reflect.DeepEqual(tc.exp, got) {
 			t.Errorf("'%s-%s/%s': expected '%#v', got '%#v'", tc.v1, tc.v2, tc.step, tc.exp, got)
 		}
@@ -556,6 +636,39 @@ func TestValues_getRangeIntervalValues(t *testing.T) {
 
 /* ==================================================================================================== */
 
+func TestValues_getOpenYearValues(t *testing.T) {
+	type testCase struct {
+		expr string
+		exp  []int
+		open bool
+		err  string
+	}
+
+	for _, tc := range []testCase{
+		{"*", []int{DefaultMinYear, 1}, true, ``},
+		{"*/5", []int{DefaultMinYear, 5}, true, ``},
+		{"2100/10", []int{2100, 10}, true, ``},
+		{"*/0", nil, true, `invalid value in field 'year' given: '*/0'`},
+		{"1969/2", nil, true, `invalid value in field 'year' given: '1969'`},
+		{"2020-2030", nil, false, ``},
+	} {
+		got, open, err := getOpenYearValues(tc.expr, newOptions(nil))
+		if !reflect.DeepEqual(tc.exp, got) {
+			t.Errorf("'%s': expected '%#v', got '%#v'", tc.expr, tc.exp, got)
+		}
+
+		if tc.open != open {
+			t.Errorf("'%s': expected '%t', got '%t'", tc.expr, tc.open, open)
+		}
+
+		if tc.err != "" || err != nil {
+			if eerr := fmt.Sprintf("%s", err); tc.err != eerr {
+				t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.err, eerr)
+			}
+		}
+	}
+}
+
 func TestValues_getMinMax(t *testing.T) {
 	type testCase struct {
 		ft     fieldType
@@ -571,9 +684,12 @@ func TestValues_getMinMax(t *testing.T) {
 		{typeMonth, 1, 12},
 		{typeDoW, 0, 6},
 		{typeYear, 1970, 2099},
+		{typeWeek, 1, 53},
+		{typeDoY, 1, 366},
+		{typeMilliseconds, 0, 999},
 		{-1, 0, 0}, // Code cannot be reached in the production code...
 	} {
-		if min, max := getMinMax(tc.ft); min != tc.expMin || max != tc.expMax {
+		if min, max := getMinMax(tc.ft, testOptions); min != tc.expMin || max != tc.expMax {
 			t.Errorf("expected min/max '%d/%d', got '%d/%d'", tc.expMin, tc.expMax, min, max)
 		}
 	}
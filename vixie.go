@@ -0,0 +1,87 @@
+// Copyright 2022 Alex Schneider. All rights reserved.
+
+package cron
+
+import (
+	"fmt"
+	"strings"
+)
+
+// vixieMacros contains the macros supported by the Vixie cron.
+var vixieMacros = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+	"@reboot":   "@reboot",
+}
+
+/* ==================================================================================================== */
+
+// fromVixie converts the given classic 5 fields expression of the Vixie cron
+// to the 7 fields syntax of this package. All non-standard special characters
+// are rejected.
+func fromVixie(expression string) (string, error) {
+	if strings.HasPrefix(expression, "@") {
+		e, ok := vixieMacros[expression]
+		if !ok {
+			return "", fmt.Errorf("unsupported macro given '%s'", expression)
+		} else if e == "@reboot" {
+			return e, nil
+		}
+
+		expression = e
+	}
+
+	fieldsParts := reFieldsMatcher.FindAllString(expression, -1)
+	if len(fieldsParts) != 5 {
+		return "", fmt.Errorf("invalid Vixie expression given '%s'", expression)
+	}
+
+	for _, part := range fieldsParts {
+		if err := checkVixieField(part); err != nil {
+			return "", err
+		}
+	}
+
+	return "0 " + strings.Join(fieldsParts, " ") + " *", nil
+}
+
+func checkVixieField(field string) error {
+	field = strings.ToUpper(field)
+
+	if i := strings.IndexAny(field, "#.?="); i >= 0 {
+		return fmt.Errorf("the special character '%c' is not supported in Vixie expressions", field[i])
+	}
+
+	// All letters have to belong to the names of the months or the days of the week.
+	for _, name := range strings.FieldsFunc(field, func(r rune) bool {
+		return r < 'A' || r > 'Z'
+	}) {
+		_, isDoW := dowMap[name]
+		_, isMonth := monthMap[name]
+
+		if !isDoW && !isMonth {
+			return fmt.Errorf("the special character '%s' is not supported in Vixie expressions", name)
+		}
+	}
+
+	for _, expr := range strings.Split(field, ",") {
+		matches := reRangeValue.FindStringSubmatch(strings.SplitN(expr, "/", 2)[0])
+		if len(matches) != 3 {
+			continue
+		}
+
+		_, _, _, v1, _ := toNumVal(matches[1])
+		_, _, _, v2, _ := toNumVal(matches[2])
+
+		if v1 > v2 {
+			return fmt.Errorf("the range '%s' is not supported in Vixie expressions", expr)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package cron
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestVixie_FromVixie(t *testing.T) {
+	type testCase struct {
+		expr string
+		exp  string
+		err  string
+	}
+
+	for _, tc := range []testCase{
+		{"*/15 9-17 * * MON-FRI", "0 */15 9-17 * * MON-FRI *", ``},
+		{"0 0 1 JAN,jul *", "0 0 0 1 JAN,jul * *", ``},
+		{"0 0 * * 7", "0 0 0 * * 7 *", ``},
+		{"@weekly", "0 0 0 * * 0 *", ``},
+		{"@reboot", "@reboot", ``},
+		{"@every", "", `unsupported macro given '@every'`},
+		{"0 0 0 * * *", "", `invalid Vixie expression given '0 0 0 * * *'`},
+		{"0 0 L * *", "", `the special character 'L' is not supported in Vixie expressions`},
+		{"0 0 15W * *", "", `the special character 'W' is not supported in Vixie expressions`},
+		{"0 0 * * 5L", "", `the special character 'L' is not supported in Vixie expressions`},
+		{"0 0 * * 1#2", "", `the special character '#' is not supported in Vixie expressions`},
+		{"0 0 ? * 1", "", `the special character '?' is not supported in Vixie expressions`},
+		{"0 R * * *", "", `the special character 'R' is not supported in Vixie expressions`},
+		{"0 . * * *", "", `the special character '.' is not supported in Vixie expressions`},
+		{"0 0 * * week=1", "", `the special character '=' is not supported in Vixie expressions`},
+		{"0 22-2 * * *", "", `the range '22-2' is not supported in Vixie expressions`},
+		{"0 0 * * FRI-MON", "", `the range 'FRI-MON' is not supported in Vixie expressions`},
+	} {
+		got, err := fromVixie(tc.expr)
+		if got != tc.exp {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.exp, got)
+		}
+
+		if tc.err != "" || err != nil {
+			if eerr := fmt.Sprintf("%s", err); tc.err != eerr {
+				t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.err, eerr)
+			}
+		}
+	}
+}
+
+func TestVixie_WithDialect(t *testing.T) {
+	type testCase struct {
+		expr string
+		exp  time.Time
+	}
+
+	for _, tc := range []testCase{
+		// Both fields are restricted, so any of them has to match.
+		{"0 0 13 * FRI", time.Date(2023, 1, 6, 0, 0, 0, 0, startupTime.Location())},
+		// One of the fields starts with `*`, so both of them have to match.
+		{"0 0 */13 * FRI", time.Date(2023, 1, 27, 0, 0, 0, 0, startupTime.Location())},
+		{"0 0 * * FRI", time.Date(2023, 1, 6, 0, 0, 0, 0, startupTime.Location())},
+		{"0 0 2 * *", time.Date(2023, 1, 2, 0, 0, 0, 0, startupTime.Location())},
+		{"@monthly", time.Date(2023, 1, 1, 0, 0, 0, 0, startupTime.Location())},
+	} {
+		s, err := Parse(tc.expr, WithDialect(DialectVixie))
+		if err != nil {
+			t.Fatalf("'%s': unexpected error: %#v", tc.expr, err)
+		}
+
+		if got := s.Next(testScheduleTime); got.String() != tc.exp.String() {
+			t.Errorf("'%s': expected '%s', got '%s'", tc.expr, tc.exp, got)
+		}
+	}
+
+	_, err := Parse("0 0 * * * | 0 30 * * *", WithDialect(DialectVixie))
+	if eerr := fmt.Sprintf("%s", err); eerr != `composite expressions are not supported in Vixie expressions` {
+		t.Errorf("unexpected error: %s", eerr)
+	}
+}